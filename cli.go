@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"order/src/models"
+	"order/src/nats/deadletter"
+	"order/src/repositories"
+	order_settings "order/src/settings"
+
+	"github.com/JohnSalazar/microservices-go-common/config"
+	common_nats "github.com/JohnSalazar/microservices-go-common/nats"
+	common_services "github.com/JohnSalazar/microservices-go-common/services"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var subcommands = map[string]func(args []string){
+	"migrate": func(args []string) { migrate() },
+	"dlq":     deadLetters,
+}
+
+// isCommand reports whether name is a subcommand, so stray positional
+// arguments such as the "true" in "-prod true" still start the service.
+func isCommand(name string) bool {
+	_, ok := subcommands[name]
+	return ok
+}
+
+func runCommand(args []string) {
+	command, ok := subcommands[args[0]]
+	if !ok {
+		log.Fatalf("unknown command: %s", args[0])
+	}
+
+	command(args[1:])
+}
+
+func connectMongo(ctx context.Context, config *config.Config) *mongo.Client {
+	client, err := repositories.NewMongoClient(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = client.Connect(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return client
+}
+
+func migrate() {
+	ctx := context.Background()
+
+	config := config.LoadConfig(*production, "./config/")
+	settings := order_settings.LoadSettings(*production, "./config/")
+	models.DefaultCurrency = settings.Money.DefaultCurrency
+
+	client := connectMongo(ctx, config)
+	defer client.Disconnect(ctx)
+
+	database := repositories.NewMongoDatabase(config, client)
+	orderRepository := repositories.NewOrderRepository(database)
+
+	migrated, err := orderRepository.MigrateMoney(ctx)
+	if err != nil {
+		log.Fatalf("money migration error after %d orders: %v", migrated, err)
+	}
+
+	log.Printf("money migration: %d orders migrated", migrated)
+}
+
+const deadLettersUsage = `usage: dlq <command>
+  list [from] [limit]          list dead letters from sequence from
+  show <seq>                   print a dead letter with its payload
+  resubmit <seq> [file]        resubmit a dead letter, with the payload from file if given
+  replay <from> <to> [subject] resubmit a range, optionally only those of subject
+  discard <seq>                delete a dead letter`
+
+func deadLetters(args []string) {
+	if len(args) == 0 {
+		log.Fatal(deadLettersUsage)
+	}
+
+	config := config.LoadConfig(*production, "./config/")
+	settings := order_settings.LoadSettings(*production, "./config/")
+
+	certificatesService := common_services.NewCertificatesService(config)
+	nc, err := common_nats.NewNats(config, certificatesService)
+	if err != nil {
+		log.Fatalf("Nats connect error: %+v", err)
+	}
+	defer nc.Close()
+
+	js, err := nc.JetStream()
+	if err != nil {
+		log.Fatalf("Nats JetStream error: %+v", err)
+	}
+
+	deadLetterQueue := deadletter.NewDeadLetterQueue(js, settings.Retry.DeadLetterStream)
+
+	switch args[0] {
+	case "list":
+		from := sequenceArg(args, 1, 1)
+		limit := int(sequenceArg(args, 2, 50))
+
+		list, err := deadLetterQueue.List(from, limit)
+		if err != nil {
+			log.Fatalf("dead letters list error: %v", err)
+		}
+
+		for _, deadLetter := range list {
+			fmt.Printf("%d\t%s\t%s\tdeliveries=%d permanent=%t\t%s\n", deadLetter.Sequence,
+				deadLetter.FailedAt.Format("2006-01-02 15:04:05"), deadLetter.Subject,
+				deadLetter.Deliveries, deadLetter.Permanent, deadLetter.Error)
+		}
+
+	case "show":
+		deadLetter, err := deadLetterQueue.Get(sequenceArg(args, 1, 0))
+		if err != nil {
+			log.Fatalf("dead letter get error: %v", err)
+		}
+
+		data, _ := json.MarshalIndent(deadLetter, "", "  ")
+		fmt.Println(string(data))
+
+	case "resubmit":
+		seq := sequenceArg(args, 1, 0)
+
+		var data []byte
+		if len(args) > 2 {
+			data, err = os.ReadFile(args[2])
+			if err != nil {
+				log.Fatalf("dead letter payload read error: %v", err)
+			}
+		}
+
+		err = deadLetterQueue.Resubmit(seq, data)
+		if err != nil {
+			log.Fatalf("dead letter resubmit error: %v", err)
+		}
+
+		log.Printf("dead letter %d resubmitted", seq)
+
+	case "replay":
+		from := sequenceArg(args, 1, 0)
+		to := sequenceArg(args, 2, 0)
+
+		subject := ""
+		if len(args) > 3 {
+			subject = args[3]
+		}
+
+		replayed, err := deadLetterQueue.Replay(from, to, subject)
+		if err != nil {
+			log.Fatalf("dead letters replay error after %d: %v", replayed, err)
+		}
+
+		log.Printf("dead letters replayed: %d", replayed)
+
+	case "discard":
+		seq := sequenceArg(args, 1, 0)
+
+		err = deadLetterQueue.Discard(seq)
+		if err != nil {
+			log.Fatalf("dead letter discard error: %v", err)
+		}
+
+		log.Printf("dead letter %d discarded", seq)
+
+	default:
+		log.Fatal(deadLettersUsage)
+	}
+}
+
+// sequenceArg parses args[i], falling back to def when it is absent. A zero
+// def makes the argument required.
+func sequenceArg(args []string, i int, def uint64) uint64 {
+	if len(args) <= i {
+		if def == 0 {
+			log.Fatal(deadLettersUsage)
+		}
+
+		return def
+	}
+
+	value, err := strconv.ParseUint(args[i], 10, 64)
+	if err != nil {
+		log.Fatalf("invalid number %q", args[i])
+	}
+
+	return value
+}
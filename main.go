@@ -6,10 +6,23 @@ import (
 	"log"
 	"order/src/application/commands"
 	"order/src/application/events"
+	"order/src/application/eventstore"
+	"order/src/application/pricing"
+	"order/src/application/promotions"
+	"order/src/application/rates"
+	"order/src/application/saga"
+	"order/src/application/tax"
 	"order/src/controllers"
+	"order/src/models"
 	order_nats "order/src/nats"
+	"order/src/nats/deadletter"
+	"order/src/nats/idempotency"
+	"order/src/nats/retry"
+	"order/src/nats/subjects"
 	"order/src/repositories"
 	"order/src/routers"
+	order_settings "order/src/settings"
+	order_tasks "order/src/tasks"
 	"os"
 	"os/signal"
 	"syscall"
@@ -40,6 +53,7 @@ import (
 type Main struct {
 	config              *config.Config
 	client              *mongo.Client
+	database            *mongo.Database
 	natsConn            *nats.Conn
 	securityKeysService common_services.SecurityKeysService
 	managerCertificates common_security.ManagerCertificates
@@ -47,11 +61,15 @@ type Main struct {
 	httpServer          httputil.HttpServer
 	consulClient        *consul.Client
 	serviceID           string
+	outboxRelayTask     *order_tasks.OutboxRelayTask
+	sagaRecoveryTask    *order_tasks.SagaRecoveryTask
+	orderExpiryTask     *order_tasks.OrderExpiryTask
 }
 
 func NewMain(
 	config *config.Config,
 	client *mongo.Client,
+	database *mongo.Database,
 	natsConn *nats.Conn,
 	securityKeysService common_services.SecurityKeysService,
 	managerCertificates common_security.ManagerCertificates,
@@ -59,10 +77,14 @@ func NewMain(
 	httpServer httputil.HttpServer,
 	consulClient *consul.Client,
 	serviceID string,
+	outboxRelayTask *order_tasks.OutboxRelayTask,
+	sagaRecoveryTask *order_tasks.SagaRecoveryTask,
+	orderExpiryTask *order_tasks.OrderExpiryTask,
 ) *Main {
 	return &Main{
 		config:              config,
 		client:              client,
+		database:            database,
 		natsConn:            natsConn,
 		securityKeysService: securityKeysService,
 		managerCertificates: managerCertificates,
@@ -70,6 +92,9 @@ func NewMain(
 		httpServer:          httpServer,
 		consulClient:        consulClient,
 		serviceID:           serviceID,
+		outboxRelayTask:     outboxRelayTask,
+		sagaRecoveryTask:    sagaRecoveryTask,
+		orderExpiryTask:     orderExpiryTask,
 	}
 }
 
@@ -82,6 +107,11 @@ func main() {
 
 	flag.Parse()
 
+	if isCommand(flag.Arg(0)) {
+		runCommand(flag.Args())
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
 
@@ -102,6 +132,11 @@ func main() {
 	}
 	log.Println("Connected to MongoDB")
 
+	err = repositories.CreateIndexes(ctx, app.database)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	defer app.natsConn.Close()
 
 	providerTracer, err := provider.NewProvider(provider.ProviderConfig{
@@ -129,6 +164,13 @@ func main() {
 		log.Fatal("MongoDB Exporter user not found!")
 	}
 
+	tasksCtx, stopTasks := context.WithCancel(context.Background())
+	defer stopTasks()
+
+	app.outboxRelayTask.Start(tasksCtx)
+	app.sagaRecoveryTask.Start(tasksCtx)
+	app.orderExpiryTask.Start(tasksCtx)
+
 	app.httpServer.RunTLSServer()
 
 	<-done
@@ -144,6 +186,8 @@ func main() {
 func startup(ctx context.Context) (*Main, error) {
 	logger := common_log.NewLogger()
 	config := config.LoadConfig(*production, "./config/")
+	settings := order_settings.LoadSettings(*production, "./config/")
+	models.DefaultCurrency = settings.Money.DefaultCurrency
 	helpers.CreateFolder(config.Folders)
 	common_validator.NewValidator("en")
 
@@ -203,6 +247,7 @@ func startup(ctx context.Context) (*Main, error) {
 		string(common_nats.OrderCreate),
 		string(common_nats.OrderStatus),
 	}
+	orderSubjects = append(orderSubjects, subjects.GetOrderSubjects()...)
 
 	_, err = common_nats.NewJetStream(nc, "order", orderSubjects)
 	if err != nil {
@@ -210,41 +255,120 @@ func startup(ctx context.Context) (*Main, error) {
 	}
 
 	storeSubjects := []string{string(common_nats.StoreBooked)}
+	storeSubjects = append(storeSubjects, subjects.GetStoreSubjects()...)
 	js, err := common_nats.NewJetStream(nc, "store2", storeSubjects)
 	if err != nil {
 		log.Fatalf("Nats JetStream create error: %+v", err)
 	}
 
-	natsPublisher := common_nats.NewPublisher(js)
+	_, err = common_nats.NewJetStream(nc, "payment2", subjects.GetPaymentSubjects())
+	if err != nil {
+		log.Fatalf("Nats JetStream create error: %+v", err)
+	}
+
+	_, err = common_nats.NewJetStream(nc, "customer2", subjects.GetCustomerSubjects())
+	if err != nil {
+		log.Fatalf("Nats JetStream create error: %+v", err)
+	}
+
+	deadLetterJs, err := common_nats.NewJetStream(nc, settings.Retry.DeadLetterStream, []string{string(subjects.OrderDeadLetter)})
+	if err != nil {
+		log.Fatalf("Nats JetStream create error: %+v", err)
+	}
 
 	database := repositories.NewMongoDatabase(config, client)
 	adminMongoDbRepository := common_repositories.NewAdminMongoDbRepository(database)
 	adminMongoDbService := common_services.NewAdminMongoDbService(config, adminMongoDbRepository)
 
 	orderRepository := repositories.NewOrderRepository(database)
+	orderEventStoreRepository := repositories.NewOrderEventStoreRepository(database)
+	orderEventStore := eventstore.NewOrderEventStore(orderEventStoreRepository)
 
 	securityKeysService := common_services.NewSecurityKeysService(config, certificatesService)
 	managerSecurityKeys := common_security.NewManagerSecurityKeys(config, securityKeysService)
 	managerTokens := common_security.NewManagerTokens(config, managerSecurityKeys)
 
-	orderEventHandler := events.NewOrderEventHandler(emailService, natsPublisher)
-	orderCommandHandler := commands.NewOrderCommandHandler(orderRepository, orderEventHandler)
+	transaction := repositories.NewMongoTransaction(client)
+	outboxRepository := repositories.NewOutboxRepository(database)
+	lockRepository := repositories.NewLockRepository(database)
+
+	outboxRelayTask := order_tasks.NewOutboxRelayTask(
+		&settings.Outbox,
+		outboxRepository,
+		lockRepository,
+		js,
+		emailService,
+		serviceID)
+
+	orderSagaRepository := repositories.NewOrderSagaRepository(database)
+	orderSaga := saga.NewOrderSagaOrchestrator(orderSagaRepository, outboxRepository)
+
+	sagaRecoveryTask := order_tasks.NewSagaRecoveryTask(
+		&settings.Saga,
+		orderSagaRepository,
+		lockRepository,
+		transaction,
+		orderSaga,
+		emailService,
+		serviceID)
+
+	orderEventHandler := events.NewOrderEventHandler(emailService, outboxRepository, orderSaga)
+	roundingPolicy := pricing.RoundingPolicy{ToleranceMinorUnits: settings.Pricing.ToleranceMinorUnits}
+	exchangeRateProvider := rates.NewFileExchangeRateProvider(settings.Money.ExchangeRatesFile)
+	taxProvider, err := tax.NewRuleTableTaxProvider(&settings.Tax)
+	if err != nil {
+		return nil, err
+	}
+
+	promotionRedemptionRepository := repositories.NewPromotionRedemptionRepository(database)
+	promotionCatalog := promotions.NewFilePromotionCatalog(settings.Promotions.File)
+	promotionEngine := promotions.NewPromotionEngine(promotionCatalog, promotionRedemptionRepository)
+
+	orderCommandHandler := commands.NewOrderCommandHandler(
+		settings.Money.BaseCurrency,
+		exchangeRateProvider,
+		roundingPolicy,
+		settings.Tax.DefaultRegion,
+		taxProvider,
+		promotionEngine,
+		transaction,
+		orderRepository,
+		orderEventStore,
+		orderEventHandler,
+	)
+
+	orderExpiryTask := order_tasks.NewOrderExpiryTask(
+		&settings.Expiry,
+		orderRepository,
+		lockRepository,
+		orderCommandHandler,
+		serviceID)
+
+	processedCommandRepository := repositories.NewProcessedCommandRepository(database)
+	idempotencyGuard := idempotency.NewIdempotencyGuard(&settings.Idempotency, processedCommandRepository)
+
+	retryPolicy := retry.NewRetryPolicy(&settings.Retry, deadLetterJs, emailService, commands.IsPermanent)
 
 	listens := order_nats.NewListen(
-		config,
 		js,
 		orderCommandHandler,
-		emailService)
+		emailService,
+		idempotencyGuard,
+		retryPolicy)
 
 	listens.Listen()
 
 	authentication := middlewares.NewAuthentication(logger, managerTokens)
-	orderController := controllers.NewOrderController(orderRepository)
-	router := routers.NewRouter(config, metricService, authentication, orderController)
+	orderController := controllers.NewOrderController(orderRepository, orderCommandHandler)
+	adminOrderController := controllers.NewAdminOrderController(orderRepository, orderCommandHandler, orderEventStore, orderSaga)
+	deadLetterQueue := deadletter.NewDeadLetterQueue(deadLetterJs, settings.Retry.DeadLetterStream)
+	deadLetterController := controllers.NewDeadLetterController(deadLetterQueue)
+	router := routers.NewRouter(config, metricService, authentication, orderController, adminOrderController, deadLetterController)
 	httpServer := httputil.NewHttpServer(config, router.RouterSetup(), certificatesService)
 	app := NewMain(
 		config,
 		client,
+		database,
 		nc,
 		securityKeysService,
 		managerCertificates,
@@ -252,6 +376,9 @@ func startup(ctx context.Context) (*Main, error) {
 		httpServer,
 		consulClient,
 		serviceID,
+		outboxRelayTask,
+		sagaRecoveryTask,
+		orderExpiryTask,
 	)
 
 	return app, nil
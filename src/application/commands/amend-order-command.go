@@ -0,0 +1,15 @@
+package commands
+
+import (
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AmendOrderCommand struct {
+	ID       primitive.ObjectID `json:"id"`
+	Products []*models.Product  `json:"products"`
+	Sum      models.Money       `json:"sum"`
+	Version  uint               `json:"version"`
+	Source   string             `json:"-"`
+}
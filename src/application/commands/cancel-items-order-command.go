@@ -0,0 +1,13 @@
+package commands
+
+import (
+	"order/src/dtos"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CancelItemsOrderCommand struct {
+	ID     primitive.ObjectID `json:"id"`
+	Items  []*dtos.CancelItem `json:"items"`
+	Source string             `json:"-"`
+}
@@ -8,16 +8,24 @@ import (
 )
 
 type CreateOrderCommand struct {
-	ID         primitive.ObjectID `json:"id"`
-	CustomerID primitive.ObjectID `json:"customerId"`
-	Products   []*models.Product  `json:"products"`
-	Stores     []*models.Store    `json:"stores"`
-	Sum        float32            `json:"sum"`
-	Discount   float32            `json:"discount"`
-	CardNumber []byte             `json:"cardNumber"`
-	Kid        string             `json:"kid"`
-	CreatedAt  time.Time          `json:"created_at"`
-	UpdatedAt  time.Time          `json:"updated_at,omitempty"`
-	Version    uint               `json:"version"`
-	Deleted    bool               `json:"deleted,omitempty"`
+	ID              primitive.ObjectID `json:"id"`
+	CustomerID      primitive.ObjectID `json:"customerId"`
+	Products        []*models.Product  `json:"products"`
+	Stores          []*models.Store    `json:"stores"`
+	Sum             models.Money       `json:"sum"`
+	Discount        models.Money       `json:"discount"`
+	Currency        string             `json:"currency"`
+	Region          string             `json:"region"`
+	Coupons         []string           `json:"coupons"`
+	ShippingAddress *models.Address    `json:"shippingAddress"`
+	BillingAddress  *models.Address    `json:"billingAddress"`
+	DeliveryMethod  string             `json:"deliveryMethod"`
+	ShippingCost    models.Money       `json:"shippingCost"`
+	CardNumber      []byte             `json:"cardNumber"`
+	Kid             string             `json:"kid"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at,omitempty"`
+	Version         uint               `json:"version"`
+	Deleted         bool               `json:"deleted,omitempty"`
+	Source          string             `json:"-"`
 }
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"errors"
+	"order/src/application/pricing"
+	"order/src/application/promotions"
+	"order/src/application/statemachine"
+	"strings"
+)
+
+var ErrOrderAlreadyExists = errors.New("already a order for this customer")
+var ErrItemsNotCancelable = errors.New("order items can no longer be canceled")
+var ErrItemsShipped = errors.New("order items have already shipped and can only be returned")
+var ErrOrderNotReturnable = errors.New("order cannot be returned")
+var ErrReturnNotFound = errors.New("return not found")
+var ErrShipmentNotFound = errors.New("shipment not found")
+var ErrOrderNotShippable = errors.New("order shipments can only be updated once payment is confirmed")
+var ErrOrderNotAmendable = errors.New("order can no longer be amended")
+var ErrVersionConflict = errors.New("order was changed by someone else")
+
+type ValidationError struct {
+	Errors []string
+}
+
+func newValidationError(result interface{}) *ValidationError {
+	return &ValidationError{
+		Errors: result.([]string),
+	}
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Errors, "")
+}
+
+func promotionError(err error) error {
+	var rejected *promotions.PromotionError
+	if errors.As(err, &rejected) {
+		return &ValidationError{Errors: []string{rejected.Error()}}
+	}
+
+	return err
+}
+
+// IsPermanent reports whether err comes from the command itself rather than
+// from infrastructure, so processing it again cannot succeed.
+func IsPermanent(err error) bool {
+	var validationError *ValidationError
+	var transitionError *statemachine.InvalidTransitionError
+	var returnTransitionError *statemachine.InvalidReturnTransitionError
+	var shipmentTransitionError *statemachine.InvalidShipmentTransitionError
+	var totalMismatchError *pricing.TotalMismatchError
+
+	switch {
+	case errors.As(err, &validationError),
+		errors.As(err, &totalMismatchError),
+		errors.As(err, &transitionError),
+		errors.As(err, &returnTransitionError),
+		errors.As(err, &shipmentTransitionError):
+		return true
+	case errors.Is(err, ErrOrderAlreadyExists),
+		errors.Is(err, ErrItemsNotCancelable),
+		errors.Is(err, ErrItemsShipped),
+		errors.Is(err, ErrOrderNotReturnable),
+		errors.Is(err, ErrOrderNotAmendable),
+		errors.Is(err, ErrVersionConflict),
+		errors.Is(err, ErrReturnNotFound),
+		errors.Is(err, ErrShipmentNotFound),
+		errors.Is(err, ErrOrderNotShippable):
+		return true
+	}
+
+	return false
+}
@@ -3,7 +3,15 @@ package commands
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
 	"order/src/application/events"
+	"order/src/application/eventstore"
+	"order/src/application/pricing"
+	"order/src/application/promotions"
+	"order/src/application/rates"
+	"order/src/application/statemachine"
+	"order/src/application/tax"
 	"order/src/dtos"
 	"order/src/models"
 	"order/src/repositories/interfaces"
@@ -12,80 +20,230 @@ import (
 	"time"
 
 	common_models "github.com/JohnSalazar/microservices-go-common/models"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type OrderCommandHandler struct {
-	orderRepository   interfaces.OrderRepository
-	orderEventHandler *events.OrderEventHandler
+	baseCurrency         string
+	exchangeRateProvider rates.ExchangeRateProvider
+	roundingPolicy       pricing.RoundingPolicy
+	defaultRegion        string
+	taxProvider          tax.TaxProvider
+	promotionEngine      *promotions.PromotionEngine
+	transaction          interfaces.Transaction
+	orderRepository      interfaces.OrderRepository
+	orderEventStore      *eventstore.OrderEventStore
+	orderEventHandler    *events.OrderEventHandler
 }
 
 func NewOrderCommandHandler(
+	baseCurrency string,
+	exchangeRateProvider rates.ExchangeRateProvider,
+	roundingPolicy pricing.RoundingPolicy,
+	defaultRegion string,
+	taxProvider tax.TaxProvider,
+	promotionEngine *promotions.PromotionEngine,
+	transaction interfaces.Transaction,
 	orderRepository interfaces.OrderRepository,
+	orderEventStore *eventstore.OrderEventStore,
 	orderEventHandler *events.OrderEventHandler,
 ) *OrderCommandHandler {
 	return &OrderCommandHandler{
-		orderRepository:   orderRepository,
-		orderEventHandler: orderEventHandler,
+		baseCurrency:         baseCurrency,
+		exchangeRateProvider: exchangeRateProvider,
+		roundingPolicy:       roundingPolicy,
+		defaultRegion:        defaultRegion,
+		taxProvider:          taxProvider,
+		promotionEngine:      promotionEngine,
+		transaction:          transaction,
+		orderRepository:      orderRepository,
+		orderEventStore:      orderEventStore,
+		orderEventHandler:    orderEventHandler,
 	}
 }
 
 func (order *OrderCommandHandler) CreateOrderCommandHandler(ctx context.Context, command *CreateOrderCommand) error {
 
+	currency := command.Currency
+	if len(currency) == 0 {
+		currency = command.Sum.Currency
+	}
+
+	region := command.Region
+	if len(region) == 0 && command.ShippingAddress != nil {
+		region = command.ShippingAddress.Country
+	}
+
+	if len(region) == 0 {
+		region = order.defaultRegion
+	}
+
 	orderDto := &dtos.AddOrder{
-		ID:         command.ID,
-		CustomerID: command.CustomerID,
-		Products:   command.Products,
-		Sum:        command.Sum,
-		Discount:   command.Discount,
-		Status:     uint(common_models.OrderCreated),
+		ID:              command.ID,
+		CustomerID:      command.CustomerID,
+		Products:        command.Products,
+		Sum:             command.Sum,
+		Discount:        command.Discount,
+		Currency:        strings.ToUpper(currency),
+		Region:          strings.ToUpper(region),
+		Coupons:         command.Coupons,
+		ShippingAddress: command.ShippingAddress,
+		BillingAddress:  command.BillingAddress,
+		DeliveryMethod:  strings.ToLower(command.DeliveryMethod),
+		ShippingCost:    command.ShippingCost,
+		Status:          uint(common_models.OrderCreated),
 	}
 
 	result := validators.ValidateAddOrder(orderDto)
 	if result != nil {
-		return errors.New(strings.Join(result.([]string), ""))
+		return newValidationError(result)
 	}
 
-	orderModel := &models.Order{
-		ID:         orderDto.ID,
-		CustomerID: orderDto.CustomerID,
-		Products:   orderDto.Products,
-		Sum:        orderDto.Sum,
-		Discount:   orderDto.Discount,
-		Status:     orderDto.Status,
-		StatusAt:   time.Now().UTC(),
-		CreatedAt:  time.Now().UTC(),
+	lines, subtotal, err := pricing.CalculateLines(orderDto.Products, orderDto.Currency)
+	if err != nil {
+		return &ValidationError{Errors: []string{err.Error()}}
 	}
 
-	orderExists, _ := order.orderRepository.FindByID(ctx, orderDto.ID)
-	if orderExists != nil {
-		return errors.New("already a order for this customer")
+	err = pricing.VerifySum(orderDto.Sum, subtotal, order.roundingPolicy)
+	if err != nil {
+		return err
 	}
 
+	exchangeRate, err := order.exchangeRateProvider.Rate(ctx, orderDto.Currency, order.baseCurrency)
+	if errors.Is(err, rates.ErrUnsupportedCurrency) {
+		return &ValidationError{Errors: []string{err.Error()}}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	taxLines, err := order.taxProvider.Calculate(ctx, orderDto.Region, lines)
+	if err != nil {
+		return err
+	}
+
+	taxTotal, err := tax.Total(taxLines, orderDto.Currency)
+	if err != nil {
+		return &ValidationError{Errors: []string{err.Error()}}
+	}
+
+	evaluation, err := order.promotionEngine.Evaluate(ctx, orderDto.CustomerID, orderDto.Coupons, lines, subtotal)
+	if err != nil {
+		return promotionError(err)
+	}
+
+	if !orderDto.Discount.IsZero() {
+		err = pricing.VerifyDiscount(orderDto.Discount, evaluation.Discount, order.roundingPolicy)
+		if err != nil {
+			return err
+		}
+	}
+
+	billingAddress := orderDto.BillingAddress
+	if billingAddress == nil {
+		billingAddress = orderDto.ShippingAddress
+	}
+
+	shippingCost := orderDto.ShippingCost
+	if shippingCost.IsZero() {
+		shippingCost = models.NewMoney(0, orderDto.Currency)
+	}
+
+	orderModel := &models.Order{
+		ID:              orderDto.ID,
+		CustomerID:      orderDto.CustomerID,
+		Products:        orderDto.Products,
+		Lines:           lines,
+		Sum:             subtotal,
+		Discount:        evaluation.Discount,
+		Promotions:      evaluation.Promotions,
+		Region:          orderDto.Region,
+		TaxLines:        taxLines,
+		Tax:             taxTotal,
+		ShippingAddress: orderDto.ShippingAddress,
+		BillingAddress:  billingAddress,
+		DeliveryMethod:  orderDto.DeliveryMethod,
+		ShippingCost:    shippingCost,
+		Currency:        orderDto.Currency,
+		ExchangeRate:    exchangeRate,
+		Status:          orderDto.Status,
+		StatusAt:        time.Now().UTC(),
+		CreatedAt:       time.Now().UTC(),
+	}
+
+	orderModel.History = appendStatusHistory(nil, orderModel.Status, orderModel.Status, orderModel.StatusAt, command.Source, 0)
+
+	return order.transaction.WithTransaction(ctx, func(ctx context.Context) error {
+		return order.createOrder(ctx, command, orderModel, evaluation)
+	})
+}
+
+func (order *OrderCommandHandler) createOrder(ctx context.Context, command *CreateOrderCommand, orderModel *models.Order, evaluation *promotions.Evaluation) error {
 	orderModel, err := order.orderRepository.Create(ctx, orderModel)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrOrderAlreadyExists
+	}
+
 	if err != nil {
 		return err
 	}
 
+	err = order.promotionEngine.Redeem(ctx, orderModel.CustomerID, orderModel.ID, evaluation)
+	if err != nil {
+		return promotionError(err)
+	}
+
 	orderEvent := &events.OrderCreatedEvent{
-		ID:         orderModel.ID,
-		CustomerID: orderModel.CustomerID,
-		Products:   orderModel.Products,
-		Sum:        orderModel.Sum,
-		Discount:   orderModel.Discount,
-		Status:     orderModel.Status,
-		StatusAt:   orderModel.StatusAt,
-		CardNumber: command.CardNumber,
-		Kid:        command.Kid,
-		CreatedAt:  orderModel.CreatedAt,
-		Version:    orderModel.Version,
+		ID:              orderModel.ID,
+		CustomerID:      orderModel.CustomerID,
+		Products:        orderModel.Products,
+		Lines:           orderModel.Lines,
+		Sum:             orderModel.Sum,
+		Discount:        orderModel.Discount,
+		Promotions:      orderModel.Promotions,
+		Region:          orderModel.Region,
+		TaxLines:        orderModel.TaxLines,
+		Tax:             orderModel.Tax,
+		ShippingAddress: orderModel.ShippingAddress,
+		BillingAddress:  orderModel.BillingAddress,
+		DeliveryMethod:  orderModel.DeliveryMethod,
+		ShippingCost:    orderModel.ShippingCost,
+		Currency:        orderModel.Currency,
+		ExchangeRate:    orderModel.ExchangeRate,
+		Status:          orderModel.Status,
+		StatusAt:        orderModel.StatusAt,
+		CardNumber:      command.CardNumber,
+		Kid:             command.Kid,
+		CreatedAt:       orderModel.CreatedAt,
+		Version:         orderModel.Version,
+		Source:          command.Source,
 	}
 
-	go order.orderEventHandler.OrderCreatedEventHandler(ctx, orderEvent)
+	err = order.orderEventStore.Append(ctx, orderModel.ID, orderModel.Version, orderEvent)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return order.orderEventHandler.OrderCreatedEventHandler(ctx, orderEvent)
 }
 
 func (order *OrderCommandHandler) UpdateStatusOrderCommandHandler(ctx context.Context, command *UpdateStatusOrderCommand) error {
+	orderModel, err := order.prepareUpdateStatus(ctx, command)
+	if err != nil || orderModel == nil {
+		return err
+	}
+
+	return order.transaction.WithTransaction(ctx, func(ctx context.Context) error {
+		return order.updateStatusOrder(ctx, command, orderModel)
+	})
+}
+
+// prepareUpdateStatus returns the order with the new status applied, or nil
+// when there is nothing to change.
+func (order *OrderCommandHandler) prepareUpdateStatus(ctx context.Context, command *UpdateStatusOrderCommand) (*models.Order, error) {
 	orderDto := *&dtos.UpdateStatusOrder{
 		ID:       command.ID,
 		Status:   command.Status,
@@ -94,44 +252,86 @@ func (order *OrderCommandHandler) UpdateStatusOrderCommandHandler(ctx context.Co
 
 	result := validators.ValidateUpdateStatusOrder(&orderDto)
 	if result != nil {
-		return errors.New(strings.Join(result.([]string), ""))
+		return nil, newValidationError(result)
 	}
 
 	orderExists, err := order.orderRepository.FindByID(ctx, orderDto.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	orderModel := &models.Order{
-		ID:        orderDto.ID,
-		Products:  orderExists.Products,
-		Stores:    orderExists.Stores,
-		Sum:       orderExists.Sum,
-		Discount:  orderExists.Discount,
-		Status:    orderDto.Status,
-		StatusAt:  orderDto.StatusAt,
-		UpdatedAt: time.Now().UTC(),
-		Version:   orderExists.Version,
+	if orderExists.Status == orderDto.Status {
+		return nil, nil
 	}
 
-	orderModel, err = order.orderRepository.Update(ctx, orderModel)
+	if command.Expected != nil && orderExists.Status != *command.Expected {
+		return nil, nil
+	}
+
+	if !command.Force {
+		err = statemachine.Transition(common_models.Status(orderExists.Status), common_models.Status(orderDto.Status))
+		if err != nil {
+			rejectedEvent := &events.OrderStatusRejectedEvent{
+				ID:             orderExists.ID,
+				CurrentStatus:  orderExists.Status,
+				RejectedStatus: orderDto.Status,
+				Reason:         err.Error(),
+				RejectedAt:     time.Now().UTC(),
+				Version:        orderExists.Version,
+			}
+
+			rejectedErr := order.transaction.WithTransaction(ctx, func(ctx context.Context) error {
+				return order.orderEventHandler.OrderStatusRejectedEventHandler(ctx, rejectedEvent)
+			})
+			if rejectedErr != nil {
+				log.Printf("order %s status rejected event error: %v\n", orderExists.ID.Hex(), rejectedErr)
+			}
+
+			return nil, err
+		}
+	}
+
+	orderModel := *orderExists
+	orderModel.Status = orderDto.Status
+	orderModel.StatusAt = orderDto.StatusAt
+	orderModel.UpdatedAt = time.Now().UTC()
+	orderModel.History = appendStatusHistory(orderExists.History, orderExists.Status, orderDto.Status, orderDto.StatusAt, command.Source, orderExists.Version+1)
+
+	return &orderModel, nil
+}
+
+func (order *OrderCommandHandler) updateStatusOrder(ctx context.Context, command *UpdateStatusOrderCommand, orderModel *models.Order) error {
+	orderModel, err := order.orderRepository.Update(ctx, orderModel)
 	if err != nil {
 		return err
 	}
 
+	if orderModel.Status == uint(common_models.OrderCanceled) {
+		err = order.promotionEngine.Release(ctx, orderModel.ID)
+		if err != nil {
+			return err
+		}
+	}
+
 	orderEvent := &events.OrderStatusUpdatedEvent{
-		ID:        orderModel.ID,
-		Products:  orderModel.Products,
-		Stores:    orderModel.Stores,
-		Status:    orderModel.Status,
-		StatusAt:  orderModel.StatusAt,
-		UpdatedAt: orderModel.UpdatedAt,
-		Version:   orderModel.Version,
+		ID:              orderModel.ID,
+		Products:        orderModel.Products,
+		Stores:          orderModel.Stores,
+		ShippingAddress: orderModel.ShippingAddress,
+		DeliveryMethod:  orderModel.DeliveryMethod,
+		Status:          orderModel.Status,
+		StatusAt:        orderModel.StatusAt,
+		UpdatedAt:       orderModel.UpdatedAt,
+		Version:         orderModel.Version,
+		Source:          command.Source,
 	}
 
-	go order.orderEventHandler.OrderStatusUpdatedEventHandler(ctx, orderEvent)
+	err = order.orderEventStore.Append(ctx, orderModel.ID, orderModel.Version, orderEvent)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return order.orderEventHandler.OrderStatusUpdatedEventHandler(ctx, orderEvent)
 }
 
 func (order *OrderCommandHandler) UpdateStoreOrderCommandHandler(ctx context.Context, command *UpdateStoreOrderCommand) error {
@@ -144,7 +344,7 @@ func (order *OrderCommandHandler) UpdateStoreOrderCommandHandler(ctx context.Con
 
 		result := validators.ValidateUpdateStoreOrder(orderDto)
 		if result != nil {
-			return errors.New(strings.Join(result.([]string), ""))
+			return newValidationError(result)
 		}
 
 		storeModel := &models.Store{
@@ -160,32 +360,518 @@ func (order *OrderCommandHandler) UpdateStoreOrderCommandHandler(ctx context.Con
 		return err
 	}
 
-	orderModel := &models.Order{
-		ID:         orderExists.ID,
-		CustomerID: orderExists.CustomerID,
-		Products:   orderExists.Products,
-		Stores:     stores,
-		Sum:        orderExists.Sum,
-		Discount:   orderExists.Discount,
-		Status:     orderExists.Status,
-		StatusAt:   orderExists.StatusAt,
-		UpdatedAt:  time.Now().UTC(),
-		Version:    orderExists.Version,
-	}
+	orderModel := *orderExists
+	orderModel.Stores = stores
+	orderModel.UpdatedAt = time.Now().UTC()
+	orderModel.Shipments = groupShipments(orderExists.Shipments, stores, orderModel.UpdatedAt)
+	orderModel.FulfillmentStatus = statemachine.FulfillmentStatus(orderModel.Shipments)
+
+	return order.transaction.WithTransaction(ctx, func(ctx context.Context) error {
+		return order.updateStoreOrder(ctx, &orderModel)
+	})
+}
 
-	orderModel, err = order.orderRepository.Update(ctx, orderModel)
+func (order *OrderCommandHandler) updateStoreOrder(ctx context.Context, orderModel *models.Order) error {
+	orderModel, err := order.orderRepository.Update(ctx, orderModel)
 	if err != nil {
 		return err
 	}
 
 	orderEvent := &events.OrderStoreUpdatedEvent{
-		ID:        orderModel.ID,
-		Stores:    stores,
-		UpdatedAt: orderModel.UpdatedAt,
-		Version:   orderModel.Version,
+		ID:                orderModel.ID,
+		Stores:            orderModel.Stores,
+		Shipments:         orderModel.Shipments,
+		FulfillmentStatus: orderModel.FulfillmentStatus,
+		UpdatedAt:         orderModel.UpdatedAt,
+		Version:           orderModel.Version,
+	}
+
+	err = order.orderEventStore.Append(ctx, orderModel.ID, orderModel.Version, orderEvent)
+	if err != nil {
+		return err
+	}
+
+	return order.orderEventHandler.OrderStoreUpdatedEventHandler(ctx, orderEvent)
+}
+
+func (order *OrderCommandHandler) UpdateShipmentOrderCommandHandler(ctx context.Context, command *UpdateShipmentOrderCommand) error {
+	orderDto := &dtos.UpdateShipmentOrder{
+		ID:             command.ID,
+		ShipmentID:     command.ShipmentID,
+		StoreID:        command.StoreID,
+		Status:         command.Status,
+		Carrier:        command.Carrier,
+		TrackingNumber: command.TrackingNumber,
+	}
+
+	result := validators.ValidateUpdateShipmentOrder(orderDto)
+	if result != nil {
+		return newValidationError(result)
+	}
+
+	orderExists, err := order.orderRepository.FindByID(ctx, orderDto.ID)
+	if err != nil {
+		return err
+	}
+
+	if orderExists.Status != uint(common_models.PaymentConfirmed) {
+		return ErrOrderNotShippable
+	}
+
+	at := command.At
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+
+	shipments := []*models.Shipment{}
+	var shipment *models.Shipment
+
+	for _, existing := range orderExists.Shipments {
+		// A store may only update its own shipment.
+		matches := existing.StoreID == orderDto.StoreID &&
+			(orderDto.ShipmentID.IsZero() || existing.ID == orderDto.ShipmentID)
+		if !matches || shipment != nil {
+			shipments = append(shipments, existing)
+			continue
+		}
+
+		updated := *existing
+		shipment = &updated
+		shipments = append(shipments, shipment)
+	}
+
+	if shipment == nil {
+		return ErrShipmentNotFound
+	}
+
+	if shipment.Status == orderDto.Status {
+		return nil
+	}
+
+	err = statemachine.ShipmentTransition(shipment.Status, orderDto.Status)
+	if err != nil {
+		return err
 	}
 
-	go order.orderEventHandler.OrderStoreUpdatedEventHandler(ctx, orderEvent)
+	shipment.Status = orderDto.Status
+	shipment.UpdatedAt = at
+	if len(orderDto.Carrier) > 0 {
+		shipment.Carrier = orderDto.Carrier
+	}
+
+	if len(orderDto.TrackingNumber) > 0 {
+		shipment.TrackingNumber = orderDto.TrackingNumber
+	}
+
+	switch shipment.Status {
+	case models.ShipmentPacked:
+		shipment.PackedAt = at
+	case models.ShipmentShipped:
+		shipment.ShippedAt = at
+	case models.ShipmentDelivered:
+		shipment.DeliveredAt = at
+	}
+
+	orderModel := *orderExists
+	orderModel.Shipments = shipments
+	orderModel.FulfillmentStatus = statemachine.FulfillmentStatus(shipments)
+	orderModel.UpdatedAt = time.Now().UTC()
+
+	return order.transaction.WithTransaction(ctx, func(ctx context.Context) error {
+		return order.updateShipmentOrder(ctx, command, &orderModel, shipment)
+	})
+}
+
+func (order *OrderCommandHandler) updateShipmentOrder(ctx context.Context, command *UpdateShipmentOrderCommand, orderModel *models.Order, shipment *models.Shipment) error {
+	orderModel, err := order.orderRepository.Update(ctx, orderModel)
+	if err != nil {
+		return err
+	}
+
+	orderEvent := &events.OrderShipmentUpdatedEvent{
+		ID:                orderModel.ID,
+		Shipment:          shipment,
+		FulfillmentStatus: orderModel.FulfillmentStatus,
+		UpdatedAt:         orderModel.UpdatedAt,
+		Version:           orderModel.Version,
+		Source:            command.Source,
+	}
+
+	err = order.orderEventStore.Append(ctx, orderModel.ID, orderModel.Version, orderEvent)
+	if err != nil {
+		return err
+	}
+
+	return order.orderEventHandler.OrderShipmentUpdatedEventHandler(ctx, orderEvent)
+}
+
+// groupShipments keeps one shipment per booked store. Existing shipments keep
+// their progress; a pending shipment whose store no longer holds any product
+// is dropped.
+func groupShipments(existing []*models.Shipment, stores []*models.Store, now time.Time) []*models.Shipment {
+	products := map[uuid.UUID][]uuid.UUID{}
+	storeIDs := []uuid.UUID{}
+	for _, store := range stores {
+		if _, ok := products[store.ID]; !ok {
+			storeIDs = append(storeIDs, store.ID)
+		}
+		products[store.ID] = append(products[store.ID], store.ProductID)
+	}
+
+	shipments := []*models.Shipment{}
+	grouped := map[uuid.UUID]bool{}
+
+	for _, shipment := range existing {
+		productIDs, ok := products[shipment.StoreID]
+		if !ok && shipment.Status == models.ShipmentPending {
+			continue
+		}
+
+		updated := *shipment
+		if ok {
+			updated.ProductIDs = productIDs
+			grouped[shipment.StoreID] = true
+		}
+		shipments = append(shipments, &updated)
+	}
+
+	for _, storeID := range storeIDs {
+		if grouped[storeID] {
+			continue
+		}
+
+		shipments = append(shipments, &models.Shipment{
+			ID:         primitive.NewObjectID(),
+			StoreID:    storeID,
+			ProductIDs: products[storeID],
+			Status:     models.ShipmentPending,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
+
+	return shipments
+}
+
+func (order *OrderCommandHandler) CancelItemsOrderCommandHandler(ctx context.Context, command *CancelItemsOrderCommand) error {
+	orderModel, orderEvent, err := order.prepareCancelItems(ctx, command)
+	if err != nil {
+		return err
+	}
+
+	return order.transaction.WithTransaction(ctx, func(ctx context.Context) error {
+		return order.cancelItemsOrder(ctx, orderModel, orderEvent)
+	})
+}
+
+func (order *OrderCommandHandler) prepareCancelItems(ctx context.Context, command *CancelItemsOrderCommand) (*models.Order, *events.OrderItemsCanceledEvent, error) {
+	orderDto := &dtos.CancelItemsOrder{
+		ID:    command.ID,
+		Items: command.Items,
+	}
+
+	result := validators.ValidateCancelItemsOrder(orderDto)
+	if result != nil {
+		return nil, nil, newValidationError(result)
+	}
+
+	orderExists, err := order.orderRepository.FindByID(ctx, orderDto.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !statemachine.CanCancelItems(common_models.Status(orderExists.Status)) {
+		return nil, nil, ErrItemsNotCancelable
+	}
+
+	quantities := map[uuid.UUID]uint{}
+	productIDs := []uuid.UUID{}
+	for _, item := range orderDto.Items {
+		quantities[item.ProductID] += item.Quantity
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	if productID, ok := statemachine.ShippedProduct(orderExists.Shipments, productIDs); ok {
+		return nil, nil, fmt.Errorf("product %s: %w", productID, ErrItemsShipped)
+	}
+
+	canceledAt := time.Now().UTC()
+	products := []*models.Product{}
+	canceledItems := []*models.CanceledItem{}
+	removed := map[uuid.UUID]bool{}
+
+	for _, product := range orderExists.Products {
+		quantity, ok := quantities[product.ID]
+		if !ok {
+			products = append(products, product)
+			continue
+		}
+		delete(quantities, product.ID)
+
+		if quantity > product.Quantity {
+			return nil, nil, &ValidationError{Errors: []string{fmt.Sprintf("product %s: cannot cancel %d of %d", product.ID, quantity, product.Quantity)}}
+		}
+
+		canceledItems = append(canceledItems, &models.CanceledItem{
+			ProductID:  product.ID,
+			Quantity:   quantity,
+			Amount:     product.Price.Multiply(int64(quantity)),
+			CanceledAt: canceledAt,
+			Source:     command.Source,
+		})
+
+		if quantity == product.Quantity {
+			removed[product.ID] = true
+			continue
+		}
+
+		remaining := *product
+		remaining.Quantity = product.Quantity - quantity
+		products = append(products, &remaining)
+	}
+
+	for productID := range quantities {
+		return nil, nil, &ValidationError{Errors: []string{fmt.Sprintf("product %s is not on this order", productID)}}
+	}
+
+	if len(products) == 0 {
+		return nil, nil, &ValidationError{Errors: []string{"every item would be canceled, cancel the order instead"}}
+	}
+
+	stores := []*models.Store{}
+	releasedStores := []*models.Store{}
+	for _, store := range orderExists.Stores {
+		if _, ok := findCanceledItem(canceledItems, store.ProductID); ok {
+			releasedStores = append(releasedStores, store)
+		}
+
+		if !removed[store.ProductID] {
+			stores = append(stores, store)
+		}
+	}
+
+	orderModel := *orderExists
+	orderModel.Products = products
+	orderModel.Stores = stores
+	orderModel.Shipments = groupShipments(orderExists.Shipments, stores, canceledAt)
+	orderModel.CanceledItems = append(append([]*models.CanceledItem{}, orderExists.CanceledItems...), canceledItems...)
+	orderModel.UpdatedAt = canceledAt
+
+	err = order.reprice(ctx, &orderModel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previousTotal, err := pricing.Total(orderExists.Sum, orderExists.Discount, orderExists.Tax, orderExists.ShippingCost)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	total, err := pricing.Total(orderModel.Sum, orderModel.Discount, orderModel.Tax, orderModel.ShippingCost)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refund, err := previousTotal.Sub(total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orderEvent := &events.OrderItemsCanceledEvent{
+		Items:          canceledItems,
+		ReleasedStores: releasedStores,
+		Total:          total,
+		Refund:         refund,
+		Paid:           orderExists.Status == uint(common_models.PaymentConfirmed),
+		Source:         command.Source,
+	}
+
+	return &orderModel, orderEvent, nil
+}
+
+func (order *OrderCommandHandler) cancelItemsOrder(ctx context.Context, orderModel *models.Order, orderEvent *events.OrderItemsCanceledEvent) error {
+	orderModel, err := order.orderRepository.Update(ctx, orderModel)
+	if err != nil {
+		return err
+	}
+
+	orderEvent.ID = orderModel.ID
+	orderEvent.Products = orderModel.Products
+	orderEvent.Lines = orderModel.Lines
+	orderEvent.Stores = orderModel.Stores
+	orderEvent.Shipments = orderModel.Shipments
+	orderEvent.Sum = orderModel.Sum
+	orderEvent.Discount = orderModel.Discount
+	orderEvent.Promotions = orderModel.Promotions
+	orderEvent.TaxLines = orderModel.TaxLines
+	orderEvent.Tax = orderModel.Tax
+	orderEvent.UpdatedAt = orderModel.UpdatedAt
+	orderEvent.Version = orderModel.Version
+
+	err = order.orderEventStore.Append(ctx, orderModel.ID, orderModel.Version, orderEvent)
+	if err != nil {
+		return err
+	}
+
+	return order.orderEventHandler.OrderItemsCanceledEventHandler(ctx, orderEvent)
+}
+
+func (order *OrderCommandHandler) AmendOrderCommandHandler(ctx context.Context, command *AmendOrderCommand) error {
+	orderExists, err := order.orderRepository.FindByID(ctx, command.ID)
+	if err != nil {
+		return err
+	}
+
+	if command.Version != orderExists.Version {
+		return ErrVersionConflict
+	}
+
+	if !statemachine.CanAmend(common_models.Status(orderExists.Status)) {
+		return ErrOrderNotAmendable
+	}
+
+	orderDto := &dtos.AddOrder{
+		ID:              orderExists.ID,
+		CustomerID:      orderExists.CustomerID,
+		Products:        command.Products,
+		Sum:             command.Sum,
+		Currency:        orderExists.Sum.Currency,
+		Region:          orderExists.Region,
+		ShippingAddress: orderExists.ShippingAddress,
+		BillingAddress:  orderExists.BillingAddress,
+		DeliveryMethod:  orderExists.DeliveryMethod,
+		ShippingCost:    orderExists.ShippingCost,
+		Status:          orderExists.Status,
+	}
+
+	// Orders placed before regions and delivery data existed are validated
+	// as pickups in the default region so they can still be amended.
+	if len(orderDto.Region) == 0 {
+		orderDto.Region = order.defaultRegion
+	}
+
+	if len(orderDto.DeliveryMethod) == 0 {
+		orderDto.DeliveryMethod = models.DeliveryPickup
+	}
+
+	result := validators.ValidateAddOrder(orderDto)
+	if result != nil {
+		return newValidationError(result)
+	}
+
+	orderModel := *orderExists
+	orderModel.Products = orderDto.Products
+	orderModel.UpdatedAt = time.Now().UTC()
+
+	err = order.reprice(ctx, &orderModel)
+	if err != nil {
+		return err
+	}
+
+	err = pricing.VerifySum(orderDto.Sum, orderModel.Sum, order.roundingPolicy)
+	if err != nil {
+		return err
+	}
+
+	previousTotal, err := pricing.Total(orderExists.Sum, orderExists.Discount, orderExists.Tax, orderExists.ShippingCost)
+	if err != nil {
+		return err
+	}
+
+	total, err := pricing.Total(orderModel.Sum, orderModel.Discount, orderModel.Tax, orderModel.ShippingCost)
+	if err != nil {
+		return err
+	}
+
+	orderEvent := &events.OrderAmendedEvent{
+		PreviousTotal: previousTotal,
+		Total:         total,
+		Source:        command.Source,
+	}
+
+	return order.transaction.WithTransaction(ctx, func(ctx context.Context) error {
+		return order.amendOrder(ctx, &orderModel, orderEvent)
+	})
+}
+
+func (order *OrderCommandHandler) amendOrder(ctx context.Context, orderModel *models.Order, orderEvent *events.OrderAmendedEvent) error {
+	orderModel, err := order.orderRepository.Update(ctx, orderModel)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrVersionConflict
+	}
+
+	if err != nil {
+		return err
+	}
+
+	orderEvent.ID = orderModel.ID
+	orderEvent.Products = orderModel.Products
+	orderEvent.Lines = orderModel.Lines
+	orderEvent.Sum = orderModel.Sum
+	orderEvent.Discount = orderModel.Discount
+	orderEvent.Promotions = orderModel.Promotions
+	orderEvent.TaxLines = orderModel.TaxLines
+	orderEvent.Tax = orderModel.Tax
+	orderEvent.UpdatedAt = orderModel.UpdatedAt
+	orderEvent.Version = orderModel.Version
+
+	err = order.orderEventStore.Append(ctx, orderModel.ID, orderModel.Version, orderEvent)
+	if err != nil {
+		return err
+	}
+
+	return order.orderEventHandler.OrderAmendedEventHandler(ctx, orderEvent)
+}
+
+// reprice recomputes lines, tax and the already applied promotions from the
+// order's current products.
+func (order *OrderCommandHandler) reprice(ctx context.Context, orderModel *models.Order) error {
+	lines, subtotal, err := pricing.CalculateLines(orderModel.Products, orderModel.Currency)
+	if err != nil {
+		return &ValidationError{Errors: []string{err.Error()}}
+	}
+
+	taxLines, err := order.taxProvider.Calculate(ctx, orderModel.Region, lines)
+	if err != nil {
+		return err
+	}
+
+	taxTotal, err := tax.Total(taxLines, subtotal.Currency)
+	if err != nil {
+		return &ValidationError{Errors: []string{err.Error()}}
+	}
+
+	evaluation, err := order.promotionEngine.Reprice(ctx, orderModel.Promotions, lines, subtotal)
+	if err != nil {
+		return err
+	}
+
+	orderModel.Lines = lines
+	orderModel.Sum = subtotal
+	orderModel.TaxLines = taxLines
+	orderModel.Tax = taxTotal
+	orderModel.Promotions = evaluation.Promotions
+	orderModel.Discount = evaluation.Discount
 
 	return nil
 }
+
+func findCanceledItem(items []*models.CanceledItem, productID uuid.UUID) (*models.CanceledItem, bool) {
+	for _, item := range items {
+		if item.ProductID == productID {
+			return item, true
+		}
+	}
+
+	return nil, false
+}
+
+func appendStatusHistory(history []*models.StatusHistory, previousStatus uint, status uint, statusAt time.Time, source string, version uint) []*models.StatusHistory {
+	return append(history, &models.StatusHistory{
+		PreviousStatus: previousStatus,
+		Status:         status,
+		StatusAt:       statusAt,
+		Source:         source,
+		Version:        version,
+	})
+}
@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"order/src/application/events"
+	"order/src/application/pricing"
+	"order/src/application/statemachine"
+	"order/src/dtos"
+	"order/src/models"
+	"order/src/validators"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (order *OrderCommandHandler) RequestReturnCommandHandler(ctx context.Context, command *RequestReturnCommand) error {
+	orderDto := &dtos.RequestReturn{
+		ID:    command.ID,
+		Items: command.Items,
+	}
+
+	result := validators.ValidateRequestReturn(orderDto)
+	if result != nil {
+		return newValidationError(result)
+	}
+
+	orderExists, err := order.orderRepository.FindByID(ctx, orderDto.ID)
+	if err != nil {
+		return err
+	}
+
+	if !statemachine.CanReturn(orderExists) {
+		return ErrOrderNotReturnable
+	}
+
+	returned := map[uuid.UUID]uint{}
+	for _, returnRequest := range orderExists.Returns {
+		if returnRequest.Status == models.ReturnRejected {
+			continue
+		}
+
+		for _, item := range returnRequest.Items {
+			returned[item.ProductID] += item.Quantity
+		}
+	}
+
+	refund := models.NewMoney(0, orderExists.Sum.Currency)
+	for _, item := range orderDto.Items {
+		product := findProduct(orderExists.Products, item.ProductID)
+		if product == nil {
+			return &ValidationError{Errors: []string{fmt.Sprintf("product %s is not on this order", item.ProductID)}}
+		}
+
+		returned[item.ProductID] += item.Quantity
+		if returned[item.ProductID] > product.Quantity {
+			return &ValidationError{Errors: []string{fmt.Sprintf("product %s: cannot return more than the %d bought", product.ID, product.Quantity)}}
+		}
+
+		itemRefund, err := pricing.Refund(orderExists, item.ProductID, item.Quantity)
+		if err != nil {
+			return &ValidationError{Errors: []string{err.Error()}}
+		}
+
+		refund, err = refund.Add(itemRefund)
+		if err != nil {
+			return err
+		}
+	}
+
+	returnID := command.ReturnID
+	if returnID.IsZero() {
+		returnID = primitive.NewObjectID()
+	}
+
+	now := time.Now().UTC()
+	returnRequest := &models.ReturnRequest{
+		ID:          returnID,
+		Items:       orderDto.Items,
+		Status:      models.ReturnRequested,
+		Refund:      refund,
+		RequestedBy: command.Source,
+		RequestedAt: now,
+		UpdatedAt:   now,
+	}
+
+	orderModel := *orderExists
+	orderModel.Returns = append(append([]*models.ReturnRequest{}, orderExists.Returns...), returnRequest)
+	orderModel.UpdatedAt = now
+
+	return order.transaction.WithTransaction(ctx, func(ctx context.Context) error {
+		return order.requestReturn(ctx, command, &orderModel, returnRequest)
+	})
+}
+
+func (order *OrderCommandHandler) requestReturn(ctx context.Context, command *RequestReturnCommand, orderModel *models.Order, returnRequest *models.ReturnRequest) error {
+	orderModel, err := order.orderRepository.Update(ctx, orderModel)
+	if err != nil {
+		return err
+	}
+
+	orderEvent := &events.OrderReturnRequestedEvent{
+		ID:        orderModel.ID,
+		Return:    returnRequest,
+		UpdatedAt: orderModel.UpdatedAt,
+		Version:   orderModel.Version,
+		Source:    command.Source,
+	}
+
+	err = order.orderEventStore.Append(ctx, orderModel.ID, orderModel.Version, orderEvent)
+	if err != nil {
+		return err
+	}
+
+	return order.orderEventHandler.OrderReturnRequestedEventHandler(ctx, orderEvent)
+}
+
+func (order *OrderCommandHandler) UpdateReturnCommandHandler(ctx context.Context, command *UpdateReturnCommand) error {
+	orderDto := &dtos.UpdateReturn{
+		ID:       command.ID,
+		ReturnID: command.ReturnID,
+		Status:   command.Status,
+		Note:     command.Note,
+		Refund:   command.Refund,
+	}
+
+	result := validators.ValidateUpdateReturn(orderDto)
+	if result != nil {
+		return newValidationError(result)
+	}
+
+	orderExists, err := order.orderRepository.FindByID(ctx, orderDto.ID)
+	if err != nil {
+		return err
+	}
+
+	returns := []*models.ReturnRequest{}
+	var returnRequest *models.ReturnRequest
+
+	for _, existing := range orderExists.Returns {
+		if existing.ID != orderDto.ReturnID {
+			returns = append(returns, existing)
+			continue
+		}
+
+		updated := *existing
+		returnRequest = &updated
+		returns = append(returns, returnRequest)
+	}
+
+	if returnRequest == nil {
+		return ErrReturnNotFound
+	}
+
+	if returnRequest.Status == orderDto.Status {
+		return nil
+	}
+
+	err = statemachine.ReturnTransition(returnRequest.Status, orderDto.Status)
+	if err != nil {
+		return err
+	}
+
+	if orderDto.Refund != nil && orderDto.Status == models.ReturnRefunded {
+		if orderDto.Refund.Currency != returnRequest.Refund.Currency || orderDto.Refund.Amount > returnRequest.Refund.Amount {
+			return &ValidationError{Errors: []string{fmt.Sprintf("refund cannot exceed %s", returnRequest.Refund)}}
+		}
+
+		returnRequest.Refund = *orderDto.Refund
+	}
+
+	now := time.Now().UTC()
+	returnRequest.Status = orderDto.Status
+	returnRequest.UpdatedBy = command.Source
+	returnRequest.UpdatedAt = now
+	if len(orderDto.Note) > 0 {
+		returnRequest.Note = orderDto.Note
+	}
+
+	orderModel := *orderExists
+	orderModel.Returns = returns
+	orderModel.UpdatedAt = now
+
+	return order.transaction.WithTransaction(ctx, func(ctx context.Context) error {
+		return order.updateReturn(ctx, command, &orderModel, returnRequest)
+	})
+}
+
+func (order *OrderCommandHandler) updateReturn(ctx context.Context, command *UpdateReturnCommand, orderModel *models.Order, returnRequest *models.ReturnRequest) error {
+	orderModel, err := order.orderRepository.Update(ctx, orderModel)
+	if err != nil {
+		return err
+	}
+
+	stores := []*models.Store{}
+	for _, store := range orderModel.Stores {
+		for _, item := range returnRequest.Items {
+			if item.ProductID == store.ProductID {
+				stores = append(stores, store)
+				break
+			}
+		}
+	}
+
+	orderEvent := &events.OrderReturnUpdatedEvent{
+		ID:        orderModel.ID,
+		Return:    returnRequest,
+		Stores:    stores,
+		UpdatedAt: orderModel.UpdatedAt,
+		Version:   orderModel.Version,
+		Source:    command.Source,
+	}
+
+	err = order.orderEventStore.Append(ctx, orderModel.ID, orderModel.Version, orderEvent)
+	if err != nil {
+		return err
+	}
+
+	return order.orderEventHandler.OrderReturnUpdatedEventHandler(ctx, orderEvent)
+}
+
+func findProduct(products []*models.Product, productID uuid.UUID) *models.Product {
+	for _, product := range products {
+		if product.ID == productID {
+			return product
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"order/src/application/events"
+	"order/src/application/pricing"
+	"order/src/dtos"
+	"order/src/models"
+
+	common_models "github.com/JohnSalazar/microservices-go-common/models"
+	"github.com/google/uuid"
+)
+
+const storeBookingFailedSource = "store:booking-failed"
+
+// StoreBookingFailedOrderCommandHandler compensates a booking the store could
+// not make. When every item failed the order is canceled, which cancels the
+// payment; otherwise only the failed items are canceled and refunded.
+func (order *OrderCommandHandler) StoreBookingFailedOrderCommandHandler(ctx context.Context, command *StoreBookingFailedOrderCommand) error {
+	orderExists, err := order.orderRepository.FindByID(ctx, command.ID)
+	if err != nil {
+		return err
+	}
+
+	if orderExists.Status == uint(common_models.OrderCanceled) {
+		return nil
+	}
+
+	source := command.Source
+	if len(source) == 0 {
+		source = storeBookingFailedSource
+	}
+
+	if len(command.Items) == 0 || coversOrder(orderExists.Products, command.Items) {
+		return order.cancelOnBookingFailed(ctx, orderExists, command.Reason, source)
+	}
+
+	orderModel, orderEvent, err := order.prepareCancelItems(ctx, &CancelItemsOrderCommand{
+		ID:     command.ID,
+		Items:  command.Items,
+		Source: source,
+	})
+	if err != nil {
+		return err
+	}
+
+	failedEvent := &events.OrderStoreBookingFailedEvent{
+		ID:              orderExists.ID,
+		CustomerID:      orderExists.CustomerID,
+		Items:           orderEvent.Items,
+		Refund:          orderEvent.Refund,
+		Reason:          command.Reason,
+		Products:        orderModel.Products,
+		ShippingAddress: orderModel.ShippingAddress,
+		DeliveryMethod:  orderModel.DeliveryMethod,
+	}
+
+	return order.transaction.WithTransaction(ctx, func(ctx context.Context) error {
+		err := order.cancelItemsOrder(ctx, orderModel, orderEvent)
+		if err != nil {
+			return err
+		}
+
+		return order.orderEventHandler.OrderStoreBookingFailedEventHandler(ctx, failedEvent)
+	})
+}
+
+func (order *OrderCommandHandler) cancelOnBookingFailed(ctx context.Context, orderExists *models.Order, reason string, source string) error {
+	statusCommand := &UpdateStatusOrderCommand{
+		ID:       orderExists.ID,
+		Status:   uint(common_models.OrderCanceled),
+		StatusAt: time.Now().UTC(),
+		Source:   source,
+	}
+
+	orderModel, err := order.prepareUpdateStatus(ctx, statusCommand)
+	if err != nil || orderModel == nil {
+		return err
+	}
+
+	refund, err := pricing.Total(orderExists.Sum, orderExists.Discount, orderExists.Tax, orderExists.ShippingCost)
+	if err != nil {
+		return err
+	}
+
+	items := []*models.CanceledItem{}
+	for _, product := range orderExists.Products {
+		items = append(items, &models.CanceledItem{
+			ProductID:  product.ID,
+			Quantity:   product.Quantity,
+			Amount:     product.Price.Multiply(int64(product.Quantity)),
+			CanceledAt: statusCommand.StatusAt,
+			Source:     source,
+		})
+	}
+
+	failedEvent := &events.OrderStoreBookingFailedEvent{
+		ID:         orderExists.ID,
+		CustomerID: orderExists.CustomerID,
+		Items:      items,
+		Canceled:   true,
+		Refund:     refund,
+		Reason:     reason,
+	}
+
+	// The booking step is failed before the cancellation compensates the saga.
+	return order.transaction.WithTransaction(ctx, func(ctx context.Context) error {
+		err := order.orderEventHandler.OrderStoreBookingFailedEventHandler(ctx, failedEvent)
+		if err != nil {
+			return err
+		}
+
+		return order.updateStatusOrder(ctx, statusCommand, orderModel)
+	})
+}
+
+func coversOrder(products []*models.Product, items []*dtos.CancelItem) bool {
+	quantities := map[uuid.UUID]uint{}
+	for _, item := range items {
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	for _, product := range products {
+		if quantities[product.ID] < product.Quantity {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,14 @@
+package commands
+
+import (
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RequestReturnCommand struct {
+	ID       primitive.ObjectID   `json:"id"`
+	ReturnID primitive.ObjectID   `json:"returnId"`
+	Items    []*models.ReturnItem `json:"items"`
+	Source   string               `json:"-"`
+}
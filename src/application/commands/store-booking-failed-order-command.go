@@ -0,0 +1,16 @@
+package commands
+
+import (
+	"order/src/dtos"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StoreBookingFailedOrderCommand reports items the store could not book. No
+// items means nothing on the order could be booked.
+type StoreBookingFailedOrderCommand struct {
+	ID     primitive.ObjectID `json:"orderId"`
+	Items  []*dtos.CancelItem `json:"items"`
+	Reason string             `json:"reason"`
+	Source string             `json:"-"`
+}
@@ -0,0 +1,16 @@
+package commands
+
+import (
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UpdateReturnCommand struct {
+	ID       primitive.ObjectID `json:"id"`
+	ReturnID primitive.ObjectID `json:"returnId"`
+	Status   string             `json:"status"`
+	Note     string             `json:"note"`
+	Refund   *models.Money      `json:"refund"`
+	Source   string             `json:"-"`
+}
@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UpdateShipmentOrderCommand struct {
+	ID             primitive.ObjectID `json:"orderId"`
+	ShipmentID     primitive.ObjectID `json:"shipmentId"`
+	StoreID        uuid.UUID          `json:"storeId"`
+	Status         string             `json:"status"`
+	Carrier        string             `json:"carrier"`
+	TrackingNumber string             `json:"trackingNumber"`
+	At             time.Time          `json:"at"`
+	Source         string             `json:"-"`
+}
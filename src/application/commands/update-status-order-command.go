@@ -10,4 +10,9 @@ type UpdateStatusOrderCommand struct {
 	ID       primitive.ObjectID `json:"id"`
 	Status   uint               `json:"status"`
 	StatusAt time.Time          `json:"status_at"`
+	Force    bool               `json:"-"`
+	Source   string             `json:"-"`
+	// Expected, when set, skips the update unless the order is still in
+	// that status, so a late change is never overwritten.
+	Expected *uint `json:"-"`
 }
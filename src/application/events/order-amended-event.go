@@ -0,0 +1,24 @@
+package events
+
+import (
+	"order/src/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderAmendedEvent struct {
+	ID            primitive.ObjectID         `json:"id"`
+	Products      []*models.Product          `json:"products"`
+	Lines         []*models.OrderLine        `json:"lines"`
+	Sum           models.Money               `json:"sum"`
+	Discount      models.Money               `json:"discount"`
+	Promotions    []*models.AppliedPromotion `json:"promotions"`
+	TaxLines      []*models.TaxLine          `json:"taxLines"`
+	Tax           models.Money               `json:"tax"`
+	PreviousTotal models.Money               `json:"previousTotal"`
+	Total         models.Money               `json:"total"`
+	UpdatedAt     time.Time                  `json:"updated_at"`
+	Version       uint                       `json:"version"`
+	Source        string                     `json:"source,omitempty"`
+}
@@ -8,15 +8,27 @@ import (
 )
 
 type OrderCreatedEvent struct {
-	ID         primitive.ObjectID `json:"id"`
-	CustomerID primitive.ObjectID `json:"customerId"`
-	Products   []*models.Product  `json:"products"`
-	Sum        float32            `json:"sum"`
-	Discount   float32            `json:"discount"`
-	Status     uint               `json:"status"`
-	StatusAt   time.Time          `json:"status_at"`
-	CardNumber []byte             `json:"cardNumber"`
-	Kid        string             `json:"kid"`
-	CreatedAt  time.Time          `json:"created_at"`
-	Version    uint               `json:"version"`
+	ID              primitive.ObjectID         `json:"id"`
+	CustomerID      primitive.ObjectID         `json:"customerId"`
+	Products        []*models.Product          `json:"products"`
+	Lines           []*models.OrderLine        `json:"lines"`
+	Sum             models.Money               `json:"sum"`
+	Discount        models.Money               `json:"discount"`
+	Promotions      []*models.AppliedPromotion `json:"promotions"`
+	Region          string                     `json:"region"`
+	TaxLines        []*models.TaxLine          `json:"taxLines"`
+	Tax             models.Money               `json:"tax"`
+	Currency        string                     `json:"currency"`
+	ExchangeRate    *models.ExchangeRate       `json:"exchangeRate"`
+	ShippingAddress *models.Address            `json:"shippingAddress"`
+	BillingAddress  *models.Address            `json:"billingAddress"`
+	DeliveryMethod  string                     `json:"deliveryMethod"`
+	ShippingCost    models.Money               `json:"shippingCost"`
+	Status          uint                       `json:"status"`
+	StatusAt        time.Time                  `json:"status_at"`
+	CardNumber      []byte                     `json:"cardNumber"`
+	Kid             string                     `json:"kid"`
+	CreatedAt       time.Time                  `json:"created_at"`
+	Version         uint                       `json:"version"`
+	Source          string                     `json:"source,omitempty"`
 }
@@ -4,40 +4,57 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"order/src/application/pricing"
+	"order/src/application/saga"
 	"order/src/dtos"
+	"order/src/models"
+	"order/src/nats/subjects"
+	"order/src/repositories/interfaces"
 	"time"
 
 	common_models "github.com/JohnSalazar/microservices-go-common/models"
 	common_nats "github.com/JohnSalazar/microservices-go-common/nats"
 	common_service "github.com/JohnSalazar/microservices-go-common/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type OrderEventHandler struct {
-	email     common_service.EmailService
-	publisher common_nats.Publisher
+	email            common_service.EmailService
+	outboxRepository interfaces.OutboxRepository
+	orderSaga        *saga.OrderSagaOrchestrator
 }
 
 func NewOrderEventHandler(
 	email common_service.EmailService,
-	publisher common_nats.Publisher,
+	outboxRepository interfaces.OutboxRepository,
+	orderSaga *saga.OrderSagaOrchestrator,
 ) *OrderEventHandler {
 	return &OrderEventHandler{
-		email:     email,
-		publisher: publisher,
+		email:            email,
+		outboxRepository: outboxRepository,
+		orderSaga:        orderSaga,
 	}
 }
 
 func (order *OrderEventHandler) OrderCreatedEventHandler(ctx context.Context, event *OrderCreatedEvent) error {
 
+	total, err := pricing.Total(event.Sum, event.Discount, event.Tax, event.ShippingCost)
+	if err != nil {
+		return err
+	}
+
 	payment := map[string]interface{}{
 		"orderId":    event.ID,
-		"total":      event.Sum - event.Discount,
+		"total":      json.Number(total.Major()),
+		"currency":   total.Currency,
+		"tax":        json.Number(event.Tax.Major()),
+		"shipping":   json.Number(event.ShippingCost.Major()),
 		"cardNumber": event.CardNumber,
 		"kid":        event.Kid,
 	}
 
 	dataPayment, _ := json.Marshal(payment)
-	err := order.publisher.Publish(string(common_nats.PaymentCreate), dataPayment)
+	err = order.orderSaga.Start(ctx, event.ID, dataPayment)
 	if err != nil {
 		return err
 	}
@@ -47,7 +64,7 @@ func (order *OrderEventHandler) OrderCreatedEventHandler(ctx context.Context, ev
 	}
 
 	dataCart, _ := json.Marshal(cart)
-	err = order.publisher.Publish(string(common_nats.OrderCreated), dataCart)
+	err = order.publish(ctx, event.ID, string(common_nats.OrderCreated), dataCart)
 	if err != nil {
 		return err
 	}
@@ -67,27 +84,221 @@ func (order *OrderEventHandler) OrderStatusUpdatedEventHandler(ctx context.Conte
 		}
 
 		dataPayment, _ := json.Marshal(updateStatusPaymentByOrder)
-		err := order.publisher.Publish(string(common_nats.PaymentCancel), dataPayment)
+		compensations := map[string][]byte{
+			saga.StepPayment: dataPayment,
+		}
+
+		found, err := order.orderSaga.Compensate(ctx, event.ID, "order canceled", compensations)
 		if err != nil {
 			return err
 		}
 
+		if !found {
+			err = order.publish(ctx, event.ID, string(common_nats.PaymentCancel), dataPayment)
+			if err != nil {
+				return err
+			}
+		}
+
 		go order.email.SendSupportMessage(fmt.Sprintf("Order ID: %s canceled", event.ID))
 	}
 
 	if event.Status == uint(common_models.PaymentConfirmed) {
 		bookStoreDto := &dtos.BookStore{
-			OrderID:  event.ID,
-			Products: event.Products,
+			OrderID:         event.ID,
+			Products:        event.Products,
+			ShippingAddress: event.ShippingAddress,
+			DeliveryMethod:  event.DeliveryMethod,
 		}
 
 		data, _ := json.Marshal(bookStoreDto)
-		err := order.publisher.Publish(string(common_nats.StoreBook), data)
+		err := order.orderSaga.Advance(ctx, event.ID, saga.StepStoreBooking, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	if event.Status == uint(common_models.SentForPaymentConfirmation) ||
+		event.Status == uint(common_models.AwaitingPaymentConfirmation) {
+		err := order.orderSaga.Progress(ctx, event.ID, saga.StepPayment, common_models.Status(event.Status).String())
 		if err != nil {
 			return err
 		}
 	}
 
+	if event.Status == uint(common_models.PaymentRejected) {
+		err := order.orderSaga.Fail(ctx, event.ID, saga.StepPayment, "payment rejected")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (order *OrderEventHandler) OrderStatusRejectedEventHandler(ctx context.Context, event *OrderStatusRejectedEvent) error {
+	data, _ := json.Marshal(event)
+	err := order.publish(ctx, event.ID, string(subjects.OrderStatusRejected), data)
+	if err != nil {
+		return err
+	}
+
+	go order.email.SendSupportMessage(fmt.Sprintf("Order ID: %s status change rejected: %s", event.ID, event.Reason))
+
+	return nil
+}
+
+func (order *OrderEventHandler) OrderItemsCanceledEventHandler(ctx context.Context, event *OrderItemsCanceledEvent) error {
+	subject := subjects.PaymentAdjust
+	if event.Paid {
+		subject = subjects.PaymentRefund
+	}
+
+	payment := map[string]interface{}{
+		"orderId":  event.ID,
+		"amount":   json.Number(event.Refund.Major()),
+		"total":    json.Number(event.Total.Major()),
+		"currency": event.Total.Currency,
+		"reason":   "items canceled",
+		"items":    event.Items,
+	}
+
+	dataPayment, _ := json.Marshal(payment)
+	err := order.publish(ctx, event.ID, string(subject), dataPayment)
+	if err != nil {
+		return err
+	}
+
+	if len(event.ReleasedStores) > 0 {
+		release := map[string]interface{}{
+			"orderId": event.ID,
+			"stores":  event.ReleasedStores,
+			"items":   event.Items,
+		}
+
+		dataRelease, _ := json.Marshal(release)
+		err = order.publish(ctx, event.ID, string(subjects.StoreRelease), dataRelease)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (order *OrderEventHandler) OrderAmendedEventHandler(ctx context.Context, event *OrderAmendedEvent) error {
+	if event.Total == event.PreviousTotal {
+		return nil
+	}
+
+	// A higher total needs a new authorization; a lower one only changes the
+	// amount to capture.
+	subject := subjects.PaymentAdjust
+	if event.Total.Amount > event.PreviousTotal.Amount {
+		subject = subjects.PaymentReauthorize
+	}
+
+	payment := map[string]interface{}{
+		"orderId":       event.ID,
+		"previousTotal": json.Number(event.PreviousTotal.Major()),
+		"total":         json.Number(event.Total.Major()),
+		"currency":      event.Total.Currency,
+		"reason":        "order amended",
+		"version":       event.Version,
+	}
+
+	data, _ := json.Marshal(payment)
+	return order.publish(ctx, event.ID, string(subject), data)
+}
+
+func (order *OrderEventHandler) OrderShipmentUpdatedEventHandler(ctx context.Context, event *OrderShipmentUpdatedEvent) error {
+	if event.FulfillmentStatus == models.FulfillmentDelivered {
+		go order.email.SendSupportMessage(fmt.Sprintf("Order ID: %s delivered", event.ID))
+	}
+
+	return nil
+}
+
+func (order *OrderEventHandler) OrderReturnRequestedEventHandler(ctx context.Context, event *OrderReturnRequestedEvent) error {
+	go order.email.SendSupportMessage(fmt.Sprintf("Order ID: %s return %s requested", event.ID, event.Return.ID.Hex()))
+
+	return nil
+}
+
+func (order *OrderEventHandler) OrderReturnUpdatedEventHandler(ctx context.Context, event *OrderReturnUpdatedEvent) error {
+	switch event.Return.Status {
+	case models.ReturnReceived:
+		restock := map[string]interface{}{
+			"orderId":  event.ID,
+			"returnId": event.Return.ID,
+			"stores":   event.Stores,
+			"items":    event.Return.Items,
+		}
+
+		data, _ := json.Marshal(restock)
+		return order.publish(ctx, event.ID, string(subjects.StoreRestock), data)
+
+	case models.ReturnRefunded:
+		refund := map[string]interface{}{
+			"orderId":  event.ID,
+			"returnId": event.Return.ID,
+			"amount":   json.Number(event.Return.Refund.Major()),
+			"currency": event.Return.Refund.Currency,
+			"reason":   "return",
+		}
+
+		data, _ := json.Marshal(refund)
+		return order.publish(ctx, event.ID, string(subjects.PaymentRefund), data)
+	}
+
+	return nil
+}
+
+func (order *OrderEventHandler) OrderStoreBookingFailedEventHandler(ctx context.Context, event *OrderStoreBookingFailedEvent) error {
+	message := "Some items of your order could not be reserved and were canceled. The difference will be refunded."
+
+	// Nothing was booked when the whole booking failed, so the cancellation
+	// must not release it; otherwise only the remaining items are rebooked.
+	if event.Canceled {
+		message = "Your order could not be reserved and was canceled. The payment will be canceled."
+
+		err := order.orderSaga.Fail(ctx, event.ID, saga.StepStoreBooking, event.Reason)
+		if err != nil {
+			return err
+		}
+	} else {
+		bookStoreDto := &dtos.BookStore{
+			OrderID:         event.ID,
+			Products:        event.Products,
+			ShippingAddress: event.ShippingAddress,
+			DeliveryMethod:  event.DeliveryMethod,
+		}
+
+		data, _ := json.Marshal(bookStoreDto)
+		err := order.orderSaga.Revise(ctx, event.ID, saga.StepStoreBooking, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	notification := map[string]interface{}{
+		"customerId": event.CustomerID,
+		"orderId":    event.ID,
+		"message":    message,
+		"items":      event.Items,
+		"refund":     json.Number(event.Refund.Major()),
+		"currency":   event.Refund.Currency,
+		"reason":     event.Reason,
+	}
+
+	data, _ := json.Marshal(notification)
+	err := order.publish(ctx, event.ID, string(subjects.CustomerNotify), data)
+	if err != nil {
+		return err
+	}
+
+	go order.email.SendSupportMessage(fmt.Sprintf("Order ID: %s store booking failed: %s", event.ID, event.Reason))
+
 	return nil
 }
 
@@ -99,10 +310,22 @@ func (order *OrderEventHandler) OrderStoreUpdatedEventHandler(ctx context.Contex
 	}
 
 	data, _ := json.Marshal(paymentStoreCommand)
-	err := order.publisher.Publish(string(common_nats.StorePayment), data)
+	err := order.orderSaga.Advance(ctx, event.ID, saga.StepStorePayment, data)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// Stores do not acknowledge StorePayment, so the step is done once it is
+	// in the outbox.
+	return order.orderSaga.Complete(ctx, event.ID, saga.StepStorePayment)
+}
+
+func (order *OrderEventHandler) publish(ctx context.Context, orderID primitive.ObjectID, subject string, data []byte) error {
+	message := &models.OutboxMessage{
+		OrderID: orderID,
+		Subject: subject,
+		Data:    string(data),
+	}
+
+	return order.outboxRepository.Add(ctx, message)
 }
@@ -0,0 +1,29 @@
+package events
+
+import (
+	"order/src/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderItemsCanceledEvent struct {
+	ID             primitive.ObjectID         `json:"id"`
+	Items          []*models.CanceledItem     `json:"items"`
+	Products       []*models.Product          `json:"products"`
+	Lines          []*models.OrderLine        `json:"lines"`
+	Stores         []*models.Store            `json:"stores"`
+	ReleasedStores []*models.Store            `json:"releasedStores"`
+	Shipments      []*models.Shipment         `json:"shipments"`
+	Sum            models.Money               `json:"sum"`
+	Discount       models.Money               `json:"discount"`
+	Promotions     []*models.AppliedPromotion `json:"promotions"`
+	TaxLines       []*models.TaxLine          `json:"taxLines"`
+	Tax            models.Money               `json:"tax"`
+	Total          models.Money               `json:"total"`
+	Refund         models.Money               `json:"refund"`
+	Paid           bool                       `json:"paid"`
+	UpdatedAt      time.Time                  `json:"updated_at"`
+	Version        uint                       `json:"version"`
+	Source         string                     `json:"source,omitempty"`
+}
@@ -0,0 +1,16 @@
+package events
+
+import (
+	"order/src/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderReturnRequestedEvent struct {
+	ID        primitive.ObjectID    `json:"id"`
+	Return    *models.ReturnRequest `json:"return"`
+	UpdatedAt time.Time             `json:"updated_at"`
+	Version   uint                  `json:"version"`
+	Source    string                `json:"source,omitempty"`
+}
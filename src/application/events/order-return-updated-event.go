@@ -0,0 +1,17 @@
+package events
+
+import (
+	"order/src/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderReturnUpdatedEvent struct {
+	ID        primitive.ObjectID    `json:"id"`
+	Return    *models.ReturnRequest `json:"return"`
+	Stores    []*models.Store       `json:"stores"`
+	UpdatedAt time.Time             `json:"updated_at"`
+	Version   uint                  `json:"version"`
+	Source    string                `json:"source,omitempty"`
+}
@@ -0,0 +1,17 @@
+package events
+
+import (
+	"order/src/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderShipmentUpdatedEvent struct {
+	ID                primitive.ObjectID `json:"id"`
+	Shipment          *models.Shipment   `json:"shipment"`
+	FulfillmentStatus string             `json:"fulfillmentStatus"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+	Version           uint               `json:"version"`
+	Source            string             `json:"source,omitempty"`
+}
@@ -0,0 +1,16 @@
+package events
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderStatusRejectedEvent struct {
+	ID             primitive.ObjectID `json:"id"`
+	CurrentStatus  uint               `json:"currentStatus"`
+	RejectedStatus uint               `json:"rejectedStatus"`
+	Reason         string             `json:"reason"`
+	RejectedAt     time.Time          `json:"rejected_at"`
+	Version        uint               `json:"version"`
+}
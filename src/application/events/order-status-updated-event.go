@@ -8,11 +8,14 @@ import (
 )
 
 type OrderStatusUpdatedEvent struct {
-	ID        primitive.ObjectID `json:"id"`
-	Products  []*models.Product  `json:"products"`
-	Stores    []*models.Store    `json:"stores"`
-	Status    uint               `json:"status"`
-	StatusAt  time.Time          `json:"status_at"`
-	UpdatedAt time.Time          `json:"updated_at"`
-	Version   uint               `json:"version"`
+	ID              primitive.ObjectID `json:"id"`
+	Products        []*models.Product  `json:"products"`
+	Stores          []*models.Store    `json:"stores"`
+	ShippingAddress *models.Address    `json:"shippingAddress"`
+	DeliveryMethod  string             `json:"deliveryMethod"`
+	Status          uint               `json:"status"`
+	StatusAt        time.Time          `json:"status_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	Version         uint               `json:"version"`
+	Source          string             `json:"source,omitempty"`
 }
@@ -0,0 +1,19 @@
+package events
+
+import (
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderStoreBookingFailedEvent struct {
+	ID              primitive.ObjectID     `json:"id"`
+	CustomerID      primitive.ObjectID     `json:"customerId"`
+	Items           []*models.CanceledItem `json:"items"`
+	Canceled        bool                   `json:"canceled"`
+	Refund          models.Money           `json:"refund"`
+	Reason          string                 `json:"reason"`
+	Products        []*models.Product      `json:"products"`
+	ShippingAddress *models.Address        `json:"shippingAddress"`
+	DeliveryMethod  string                 `json:"deliveryMethod"`
+}
@@ -8,8 +8,10 @@ import (
 )
 
 type OrderStoreUpdatedEvent struct {
-	ID        primitive.ObjectID `json:"id"`
-	Stores    []*models.Store    `json:"stores"`
-	UpdatedAt time.Time          `json:"updated_at"`
-	Version   uint               `json:"version"`
+	ID                primitive.ObjectID `json:"id"`
+	Stores            []*models.Store    `json:"stores"`
+	Shipments         []*models.Shipment `json:"shipments"`
+	FulfillmentStatus string             `json:"fulfillmentStatus"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+	Version           uint               `json:"version"`
 }
@@ -0,0 +1,236 @@
+package eventstore
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"order/src/application/events"
+	"order/src/models"
+)
+
+const (
+	OrderCreatedEventType         = "OrderCreated"
+	OrderStatusUpdatedEventType   = "OrderStatusUpdated"
+	OrderStoreUpdatedEventType    = "OrderStoreUpdated"
+	OrderItemsCanceledEventType   = "OrderItemsCanceled"
+	OrderAmendedEventType         = "OrderAmended"
+	OrderShipmentUpdatedEventType = "OrderShipmentUpdated"
+	OrderReturnRequestedEventType = "OrderReturnRequested"
+	OrderReturnUpdatedEventType   = "OrderReturnUpdated"
+)
+
+func eventType(event interface{}) (string, interface{}, error) {
+	switch e := event.(type) {
+	case *events.OrderCreatedEvent:
+		stored := *e
+		stored.CardNumber = nil
+		stored.Kid = ""
+		return OrderCreatedEventType, &stored, nil
+	case *events.OrderStatusUpdatedEvent:
+		return OrderStatusUpdatedEventType, e, nil
+	case *events.OrderStoreUpdatedEvent:
+		return OrderStoreUpdatedEventType, e, nil
+	case *events.OrderItemsCanceledEvent:
+		return OrderItemsCanceledEventType, e, nil
+	case *events.OrderAmendedEvent:
+		return OrderAmendedEventType, e, nil
+	case *events.OrderShipmentUpdatedEvent:
+		return OrderShipmentUpdatedEventType, e, nil
+	case *events.OrderReturnRequestedEvent:
+		return OrderReturnRequestedEventType, e, nil
+	case *events.OrderReturnUpdatedEvent:
+		return OrderReturnUpdatedEventType, e, nil
+	}
+
+	return "", nil, fmt.Errorf("unknown order event %T", event)
+}
+
+func apply(order *models.Order, storedEvent *models.StoredEvent) (*models.Order, error) {
+	switch storedEvent.Type {
+	case OrderCreatedEventType:
+		event := &events.OrderCreatedEvent{}
+		if err := json.Unmarshal([]byte(storedEvent.Data), event); err != nil {
+			return nil, err
+		}
+
+		return &models.Order{
+			ID:              event.ID,
+			CustomerID:      event.CustomerID,
+			Products:        event.Products,
+			Lines:           event.Lines,
+			Sum:             event.Sum,
+			Discount:        event.Discount,
+			Promotions:      event.Promotions,
+			Region:          event.Region,
+			TaxLines:        event.TaxLines,
+			Tax:             event.Tax,
+			Currency:        event.Currency,
+			ExchangeRate:    event.ExchangeRate,
+			ShippingAddress: event.ShippingAddress,
+			BillingAddress:  event.BillingAddress,
+			DeliveryMethod:  event.DeliveryMethod,
+			ShippingCost:    event.ShippingCost,
+			Status:          event.Status,
+			StatusAt:        event.StatusAt,
+			History: []*models.StatusHistory{{
+				PreviousStatus: event.Status,
+				Status:         event.Status,
+				StatusAt:       event.StatusAt,
+				Source:         event.Source,
+				Version:        event.Version,
+			}},
+			CreatedAt: event.CreatedAt,
+			Version:   event.Version,
+		}, nil
+
+	case OrderStatusUpdatedEventType:
+		if order == nil {
+			return nil, fmt.Errorf("order %s: %s before %s", storedEvent.OrderID.Hex(), storedEvent.Type, OrderCreatedEventType)
+		}
+
+		event := &events.OrderStatusUpdatedEvent{}
+		if err := json.Unmarshal([]byte(storedEvent.Data), event); err != nil {
+			return nil, err
+		}
+
+		order.History = append(order.History, &models.StatusHistory{
+			PreviousStatus: order.Status,
+			Status:         event.Status,
+			StatusAt:       event.StatusAt,
+			Source:         event.Source,
+			Version:        event.Version,
+		})
+		order.Products = event.Products
+		order.Stores = event.Stores
+		order.Status = event.Status
+		order.StatusAt = event.StatusAt
+		order.UpdatedAt = event.UpdatedAt
+		order.Version = event.Version
+
+		return order, nil
+
+	case OrderStoreUpdatedEventType:
+		if order == nil {
+			return nil, fmt.Errorf("order %s: %s before %s", storedEvent.OrderID.Hex(), storedEvent.Type, OrderCreatedEventType)
+		}
+
+		event := &events.OrderStoreUpdatedEvent{}
+		if err := json.Unmarshal([]byte(storedEvent.Data), event); err != nil {
+			return nil, err
+		}
+
+		order.Stores = event.Stores
+		order.Shipments = event.Shipments
+		order.FulfillmentStatus = event.FulfillmentStatus
+		order.UpdatedAt = event.UpdatedAt
+		order.Version = event.Version
+
+		return order, nil
+
+	case OrderItemsCanceledEventType:
+		if order == nil {
+			return nil, fmt.Errorf("order %s: %s before %s", storedEvent.OrderID.Hex(), storedEvent.Type, OrderCreatedEventType)
+		}
+
+		event := &events.OrderItemsCanceledEvent{}
+		if err := json.Unmarshal([]byte(storedEvent.Data), event); err != nil {
+			return nil, err
+		}
+
+		order.CanceledItems = append(order.CanceledItems, event.Items...)
+		order.Products = event.Products
+		order.Lines = event.Lines
+		order.Stores = event.Stores
+		order.Shipments = event.Shipments
+		order.Sum = event.Sum
+		order.Discount = event.Discount
+		order.Promotions = event.Promotions
+		order.TaxLines = event.TaxLines
+		order.Tax = event.Tax
+		order.UpdatedAt = event.UpdatedAt
+		order.Version = event.Version
+
+		return order, nil
+
+	case OrderShipmentUpdatedEventType:
+		if order == nil {
+			return nil, fmt.Errorf("order %s: %s before %s", storedEvent.OrderID.Hex(), storedEvent.Type, OrderCreatedEventType)
+		}
+
+		event := &events.OrderShipmentUpdatedEvent{}
+		if err := json.Unmarshal([]byte(storedEvent.Data), event); err != nil {
+			return nil, err
+		}
+
+		for i, shipment := range order.Shipments {
+			if shipment.ID == event.Shipment.ID {
+				order.Shipments[i] = event.Shipment
+			}
+		}
+		order.FulfillmentStatus = event.FulfillmentStatus
+		order.UpdatedAt = event.UpdatedAt
+		order.Version = event.Version
+
+		return order, nil
+
+	case OrderAmendedEventType:
+		if order == nil {
+			return nil, fmt.Errorf("order %s: %s before %s", storedEvent.OrderID.Hex(), storedEvent.Type, OrderCreatedEventType)
+		}
+
+		event := &events.OrderAmendedEvent{}
+		if err := json.Unmarshal([]byte(storedEvent.Data), event); err != nil {
+			return nil, err
+		}
+
+		order.Products = event.Products
+		order.Lines = event.Lines
+		order.Sum = event.Sum
+		order.Discount = event.Discount
+		order.Promotions = event.Promotions
+		order.TaxLines = event.TaxLines
+		order.Tax = event.Tax
+		order.UpdatedAt = event.UpdatedAt
+		order.Version = event.Version
+
+		return order, nil
+
+	case OrderReturnRequestedEventType:
+		if order == nil {
+			return nil, fmt.Errorf("order %s: %s before %s", storedEvent.OrderID.Hex(), storedEvent.Type, OrderCreatedEventType)
+		}
+
+		event := &events.OrderReturnRequestedEvent{}
+		if err := json.Unmarshal([]byte(storedEvent.Data), event); err != nil {
+			return nil, err
+		}
+
+		order.Returns = append(order.Returns, event.Return)
+		order.UpdatedAt = event.UpdatedAt
+		order.Version = event.Version
+
+		return order, nil
+
+	case OrderReturnUpdatedEventType:
+		if order == nil {
+			return nil, fmt.Errorf("order %s: %s before %s", storedEvent.OrderID.Hex(), storedEvent.Type, OrderCreatedEventType)
+		}
+
+		event := &events.OrderReturnUpdatedEvent{}
+		if err := json.Unmarshal([]byte(storedEvent.Data), event); err != nil {
+			return nil, err
+		}
+
+		for i, returnRequest := range order.Returns {
+			if returnRequest.ID == event.Return.ID {
+				order.Returns[i] = event.Return
+			}
+		}
+		order.UpdatedAt = event.UpdatedAt
+		order.Version = event.Version
+
+		return order, nil
+	}
+
+	return nil, fmt.Errorf("unknown stored event type %s", storedEvent.Type)
+}
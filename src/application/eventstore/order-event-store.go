@@ -0,0 +1,126 @@
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"order/src/models"
+	"order/src/repositories/interfaces"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const snapshotInterval uint = 10
+
+var ErrOrderNotFound = errors.New("order has no events")
+
+type OrderEventStore struct {
+	repository interfaces.OrderEventStoreRepository
+}
+
+func NewOrderEventStore(
+	repository interfaces.OrderEventStoreRepository,
+) *OrderEventStore {
+	return &OrderEventStore{
+		repository: repository,
+	}
+}
+
+func (s *OrderEventStore) Append(ctx context.Context, orderID primitive.ObjectID, version uint, event interface{}) error {
+	typeName, storedData, err := eventType(event)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(storedData)
+	if err != nil {
+		return err
+	}
+
+	storedEvent := &models.StoredEvent{
+		OrderID:    orderID,
+		Version:    version,
+		Type:       typeName,
+		Data:       string(data),
+		OccurredAt: time.Now().UTC(),
+	}
+
+	err = s.repository.Append(ctx, storedEvent)
+	if err != nil {
+		return err
+	}
+
+	if version > 0 && version%snapshotInterval == 0 {
+		return s.snapshot(ctx, orderID, storedEvent.OccurredAt)
+	}
+
+	return nil
+}
+
+func (s *OrderEventStore) Load(ctx context.Context, orderID primitive.ObjectID) (*models.Order, error) {
+	return s.LoadAt(ctx, orderID, time.Time{})
+}
+
+func (s *OrderEventStore) LoadAt(ctx context.Context, orderID primitive.ObjectID, at time.Time) (*models.Order, error) {
+	var order *models.Order
+	var fromVersion uint
+
+	snapshot, err := s.repository.FindLatestSnapshot(ctx, orderID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot != nil {
+		order = &models.Order{}
+		if err := json.Unmarshal([]byte(snapshot.State), order); err != nil {
+			return nil, err
+		}
+		fromVersion = snapshot.Version + 1
+	}
+
+	storedEvents, err := s.repository.FindByOrderID(ctx, orderID, fromVersion, at)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, storedEvent := range storedEvents {
+		order, err = apply(order, storedEvent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+
+	return order, nil
+}
+
+func (s *OrderEventStore) Events(ctx context.Context, orderID primitive.ObjectID) ([]*models.StoredEvent, error) {
+	return s.repository.FindByOrderID(ctx, orderID, 0, time.Time{})
+}
+
+func (s *OrderEventStore) snapshot(ctx context.Context, orderID primitive.ObjectID, occurredAt time.Time) error {
+	order, err := s.Load(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	state, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+
+	snapshot := &models.OrderSnapshot{
+		OrderID:    orderID,
+		Version:    order.Version,
+		State:      string(state),
+		OccurredAt: occurredAt,
+		TakenAt:    time.Now().UTC(),
+	}
+
+	return s.repository.SaveSnapshot(ctx, snapshot)
+}
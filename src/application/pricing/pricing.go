@@ -0,0 +1,155 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+
+	"order/src/models"
+
+	"github.com/google/uuid"
+)
+
+var ErrEmptyOrder = errors.New("order has no products")
+
+// RoundingPolicy states how far, in minor units, a declared amount may drift
+// from the amount computed here. Line totals are exact (unit price in minor
+// units times quantity), so a tolerance is only needed for callers that still
+// round decimal prices on their side.
+type RoundingPolicy struct {
+	ToleranceMinorUnits int64
+}
+
+type TotalMismatchError struct {
+	Field    string
+	Declared models.Money
+	Computed models.Money
+}
+
+func (e *TotalMismatchError) Error() string {
+	return fmt.Sprintf("declared %s %s does not match computed %s %s", e.Field, e.Declared, e.Field, e.Computed)
+}
+
+func CalculateLines(products []*models.Product, currency string) ([]*models.OrderLine, models.Money, error) {
+	if len(products) == 0 {
+		return nil, models.Money{}, ErrEmptyOrder
+	}
+
+	lines := []*models.OrderLine{}
+	subtotal := models.NewMoney(0, currency)
+
+	for _, product := range products {
+		line := &models.OrderLine{
+			ProductID: product.ID,
+			Quantity:  product.Quantity,
+			Category:  product.Category,
+			Currency:  product.Price.Currency,
+			UnitPrice: product.Price,
+			Total:     product.Price.Multiply(int64(product.Quantity)),
+		}
+
+		var err error
+		subtotal, err = subtotal.Add(line.Total)
+		if err != nil {
+			return nil, models.Money{}, err
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, subtotal, nil
+}
+
+func VerifySum(declared models.Money, computed models.Money, policy RoundingPolicy) error {
+	return verify("sum", declared, computed, policy)
+}
+
+func VerifyDiscount(declared models.Money, computed models.Money, policy RoundingPolicy) error {
+	return verify("discount", declared, computed, policy)
+}
+
+func verify(field string, declared models.Money, computed models.Money, policy RoundingPolicy) error {
+	difference := declared.Amount - computed.Amount
+	if difference < 0 {
+		difference = -difference
+	}
+
+	if declared.Currency != computed.Currency || difference > policy.ToleranceMinorUnits {
+		return &TotalMismatchError{Field: field, Declared: declared, Computed: computed}
+	}
+
+	return nil
+}
+
+// Total is what the customer pays: the subtotal less discounts, plus tax and
+// shipping.
+func Total(sum models.Money, discount models.Money, tax models.Money, shipping models.Money) (models.Money, error) {
+	total, err := sum.Sub(discount)
+	if err != nil {
+		return models.Money{}, err
+	}
+
+	total, err = total.Add(tax)
+	if err != nil {
+		return models.Money{}, err
+	}
+
+	return total.Add(shipping)
+}
+
+// Refund is what the customer gets back for quantity units of a product: the
+// units' line value less their share of the order discount, plus their share
+// of the tax charged on the line.
+func Refund(order *models.Order, productID uuid.UUID, quantity uint) (models.Money, error) {
+	var line *models.OrderLine
+	for _, orderLine := range order.Lines {
+		if orderLine.ProductID == productID {
+			line = orderLine
+		}
+	}
+
+	if line == nil {
+		for _, product := range order.Products {
+			if product.ID == productID {
+				line = &models.OrderLine{
+					ProductID: product.ID,
+					Quantity:  product.Quantity,
+					UnitPrice: product.Price,
+					Total:     product.Price.Multiply(int64(product.Quantity)),
+				}
+			}
+		}
+	}
+
+	if line == nil || line.Quantity == 0 {
+		return models.Money{}, fmt.Errorf("product %s is not on this order", productID)
+	}
+
+	value := line.UnitPrice.Multiply(int64(quantity))
+	refund := value
+
+	if !order.Discount.IsZero() && order.Sum.Amount > 0 {
+		share := models.NewMoney(order.Discount.Amount*value.Amount/order.Sum.Amount, order.Discount.Currency)
+
+		var err error
+		refund, err = refund.Sub(share)
+		if err != nil {
+			return models.Money{}, err
+		}
+	}
+
+	for _, taxLine := range order.TaxLines {
+		if taxLine.ProductID != productID {
+			continue
+		}
+
+		share := models.NewMoney(taxLine.Amount.Amount*int64(quantity)/int64(line.Quantity), taxLine.Amount.Currency)
+
+		var err error
+		refund, err = refund.Add(share)
+		if err != nil {
+			return models.Money{}, err
+		}
+	}
+
+	return refund, nil
+}
@@ -0,0 +1,71 @@
+package promotions
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"order/src/models"
+)
+
+type PromotionCatalog interface {
+	Promotions(ctx context.Context) ([]*models.Promotion, error)
+}
+
+type promotionsFile struct {
+	Promotions []*models.Promotion `json:"promotions"`
+}
+
+// FilePromotionCatalog serves promotions from a JSON file, reloading it
+// whenever it changes on disk.
+type FilePromotionCatalog struct {
+	path       string
+	mutex      sync.Mutex
+	modTime    time.Time
+	promotions []*models.Promotion
+}
+
+func NewFilePromotionCatalog(
+	path string,
+) *FilePromotionCatalog {
+	return &FilePromotionCatalog{
+		path: path,
+	}
+}
+
+func (c *FilePromotionCatalog) Promotions(ctx context.Context) ([]*models.Promotion, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.promotions != nil && info.ModTime().Equal(c.modTime) {
+		return c.promotions, nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := &promotionsFile{}
+	err = json.Unmarshal(data, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, promotion := range contents.Promotions {
+		promotion.Code = strings.ToUpper(promotion.Code)
+	}
+
+	c.promotions = contents.Promotions
+	c.modTime = info.ModTime()
+
+	return c.promotions, nil
+}
@@ -0,0 +1,415 @@
+package promotions
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"order/src/models"
+	"order/src/repositories/interfaces"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PromotionError struct {
+	Code   string
+	Reason string
+}
+
+func (e *PromotionError) Error() string {
+	return fmt.Sprintf("coupon %s %s", e.Code, e.Reason)
+}
+
+type Evaluation struct {
+	Promotions []*models.AppliedPromotion
+	Discount   models.Money
+	limits     map[string]uint
+}
+
+type candidate struct {
+	promotion *models.Promotion
+	amount    models.Money
+}
+
+type PromotionEngine struct {
+	catalog                       PromotionCatalog
+	promotionRedemptionRepository interfaces.PromotionRedemptionRepository
+}
+
+func NewPromotionEngine(
+	catalog PromotionCatalog,
+	promotionRedemptionRepository interfaces.PromotionRedemptionRepository,
+) *PromotionEngine {
+	return &PromotionEngine{
+		catalog:                       catalog,
+		promotionRedemptionRepository: promotionRedemptionRepository,
+	}
+}
+
+// Evaluate works out which promotions apply to the order and how much each
+// one takes off. Coupons the customer asked for must all apply or the order
+// is refused; automatic promotions are added silently when eligible.
+//
+// Stacking: a coupon that is not stackable can only be used on its own. When
+// no such coupon is given, every stackable promotion is combined, unless a
+// single non-stackable automatic promotion is worth more and no coupons were
+// given. The discount never exceeds the subtotal.
+func (e *PromotionEngine) Evaluate(
+	ctx context.Context,
+	customerID primitive.ObjectID,
+	codes []string,
+	lines []*models.OrderLine,
+	subtotal models.Money,
+) (*Evaluation, error) {
+	catalog, err := e.catalog.Promotions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	coupons := []*candidate{}
+	seen := map[string]bool{}
+
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if len(code) == 0 || seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		promotion := findPromotion(catalog, code)
+		if promotion == nil {
+			return nil, &PromotionError{Code: code, Reason: "does not exist"}
+		}
+
+		amount, reason, err := e.amount(ctx, customerID, promotion, lines, subtotal, now)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(reason) > 0 {
+			return nil, &PromotionError{Code: code, Reason: reason}
+		}
+
+		coupons = append(coupons, &candidate{promotion: promotion, amount: amount})
+	}
+
+	for _, coupon := range coupons {
+		if !coupon.promotion.Stackable && len(coupons) > 1 {
+			return nil, &PromotionError{Code: coupon.promotion.Code, Reason: "cannot be combined with other coupons"}
+		}
+	}
+
+	applied := coupons
+	if len(coupons) != 1 || coupons[0].promotion.Stackable {
+		var best *candidate
+
+		for _, promotion := range catalog {
+			if len(promotion.Code) > 0 {
+				continue
+			}
+
+			amount, reason, err := e.amount(ctx, customerID, promotion, lines, subtotal, now)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(reason) > 0 {
+				continue
+			}
+
+			automatic := &candidate{promotion: promotion, amount: amount}
+			if promotion.Stackable {
+				applied = append(applied, automatic)
+			} else if best == nil || amount.Amount > best.amount.Amount {
+				best = automatic
+			}
+		}
+
+		if best != nil && len(coupons) == 0 && best.amount.Amount > candidatesTotal(applied, subtotal.Currency).Amount {
+			applied = []*candidate{best}
+		}
+	}
+
+	evaluation := &Evaluation{
+		Promotions: []*models.AppliedPromotion{},
+		Discount:   models.NewMoney(0, subtotal.Currency),
+		limits:     map[string]uint{},
+	}
+
+	remaining := subtotal.Amount
+	for _, candidate := range applied {
+		amount := candidate.amount
+		if amount.Amount > remaining {
+			amount.Amount = remaining
+		}
+		remaining -= amount.Amount
+
+		if amount.IsZero() {
+			continue
+		}
+
+		evaluation.Promotions = append(evaluation.Promotions, &models.AppliedPromotion{
+			Code:   candidate.promotion.Code,
+			Name:   candidate.promotion.Name,
+			Type:   candidate.promotion.Type,
+			Amount: amount,
+		})
+		evaluation.Discount.Amount += amount.Amount
+		evaluation.limits[promotionKey(candidate.promotion)] = candidate.promotion.PerCustomerLimit
+	}
+
+	return evaluation, nil
+}
+
+// Redeem records the usage of every applied promotion for the order. It must
+// run in the same transaction as the order insert.
+func (e *PromotionEngine) Redeem(ctx context.Context, customerID primitive.ObjectID, orderID primitive.ObjectID, evaluation *Evaluation) error {
+	for _, applied := range evaluation.Promotions {
+		key := applied.Code
+		if len(key) == 0 {
+			key = applied.Name
+		}
+
+		redemption := &models.PromotionRedemption{
+			Code:       key,
+			CustomerID: customerID,
+			OrderID:    orderID,
+			RedeemedAt: time.Now().UTC(),
+		}
+
+		redeemed, err := e.promotionRedemptionRepository.Redeem(ctx, redemption, evaluation.limits[key])
+		if err != nil {
+			return err
+		}
+
+		if !redeemed {
+			return &PromotionError{Code: key, Reason: "has reached its usage limit"}
+		}
+	}
+
+	return nil
+}
+
+// Reprice recomputes the promotions already applied to an order after its
+// lines changed. Usage was counted when the order was placed, so limits and
+// validity windows are not checked again; promotions that no longer apply
+// (e.g. the order fell under the minimum) are dropped.
+func (e *PromotionEngine) Reprice(ctx context.Context, applied []*models.AppliedPromotion, lines []*models.OrderLine, subtotal models.Money) (*Evaluation, error) {
+	catalog, err := e.catalog.Promotions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := &Evaluation{
+		Promotions: []*models.AppliedPromotion{},
+		Discount:   models.NewMoney(0, subtotal.Currency),
+		limits:     map[string]uint{},
+	}
+
+	remaining := subtotal.Amount
+	for _, previous := range applied {
+		amount := previous.Amount
+
+		promotion := findPromotionByKey(catalog, previous.Code, previous.Name)
+		if promotion != nil {
+			var reason string
+			amount, reason = discount(promotion, lines, subtotal)
+			if len(reason) > 0 {
+				continue
+			}
+		}
+
+		if amount.Amount > remaining {
+			amount.Amount = remaining
+		}
+		remaining -= amount.Amount
+
+		if amount.IsZero() {
+			continue
+		}
+
+		evaluation.Promotions = append(evaluation.Promotions, &models.AppliedPromotion{
+			Code:   previous.Code,
+			Name:   previous.Name,
+			Type:   previous.Type,
+			Amount: amount,
+		})
+		evaluation.Discount.Amount += amount.Amount
+	}
+
+	return evaluation, nil
+}
+
+// Release gives back the promotion usage held by a cancelled order.
+func (e *PromotionEngine) Release(ctx context.Context, orderID primitive.ObjectID) error {
+	return e.promotionRedemptionRepository.Release(ctx, orderID)
+}
+
+func (e *PromotionEngine) amount(
+	ctx context.Context,
+	customerID primitive.ObjectID,
+	promotion *models.Promotion,
+	lines []*models.OrderLine,
+	subtotal models.Money,
+	now time.Time,
+) (models.Money, string, error) {
+	none := models.NewMoney(0, subtotal.Currency)
+
+	if promotion.StartsAt != nil && now.Before(*promotion.StartsAt) {
+		return none, "is not active yet", nil
+	}
+
+	if promotion.EndsAt != nil && now.After(*promotion.EndsAt) {
+		return none, "has expired", nil
+	}
+
+	if promotion.PerCustomerLimit > 0 {
+		usage, err := e.promotionRedemptionRepository.Usage(ctx, promotionKey(promotion), customerID)
+		if err != nil {
+			return none, "", err
+		}
+
+		if usage >= promotion.PerCustomerLimit {
+			return none, "has reached its usage limit", nil
+		}
+	}
+
+	amount, reason := discount(promotion, lines, subtotal)
+
+	return amount, reason, nil
+}
+
+// discount prices a promotion against the lines, without the validity
+// window and usage checks that only matter when the promotion is redeemed.
+func discount(promotion *models.Promotion, lines []*models.OrderLine, subtotal models.Money) (models.Money, string) {
+	none := models.NewMoney(0, subtotal.Currency)
+
+	if !promotion.MinOrder.IsZero() {
+		if promotion.MinOrder.Currency != subtotal.Currency || subtotal.Amount < promotion.MinOrder.Amount {
+			return none, fmt.Sprintf("requires a minimum order of %s", promotion.MinOrder)
+		}
+	}
+
+	eligible := eligibleLines(promotion, lines)
+	amount := none
+
+	switch promotion.Type {
+	case models.PromotionPercentage:
+		percentage, ok := new(big.Rat).SetString(promotion.Percentage)
+		if !ok || percentage.Sign() <= 0 {
+			return none, "is misconfigured"
+		}
+
+		rate := percentage.Quo(percentage, big.NewRat(100, 1))
+		amount = linesTotal(eligible, subtotal.Currency).MultiplyRate(rate)
+	case models.PromotionFixed:
+		if promotion.Amount.Currency != subtotal.Currency {
+			return none, fmt.Sprintf("is not valid for %s orders", subtotal.Currency)
+		}
+
+		amount = promotion.Amount
+		if eligibleTotal := linesTotal(eligible, subtotal.Currency); amount.Amount > eligibleTotal.Amount {
+			amount = eligibleTotal
+		}
+	case models.PromotionBuyXGetY:
+		group := promotion.BuyQuantity + promotion.GetQuantity
+		if promotion.BuyQuantity == 0 || promotion.GetQuantity == 0 {
+			return none, "is misconfigured"
+		}
+
+		for _, candidate := range eligible {
+			free := candidate.quantity / group * promotion.GetQuantity
+			amount.Amount += candidate.unitPrice.Multiply(int64(free)).Amount
+		}
+	default:
+		return none, "is misconfigured"
+	}
+
+	if amount.IsZero() {
+		return none, "does not apply to this order"
+	}
+
+	return amount, ""
+}
+
+type eligibleLine struct {
+	quantity  uint
+	unitPrice models.Money
+	total     models.Money
+}
+
+func eligibleLines(promotion *models.Promotion, lines []*models.OrderLine) []*eligibleLine {
+	products := map[uuid.UUID]bool{}
+	for _, productID := range promotion.ProductIDs {
+		products[productID] = true
+	}
+
+	eligible := []*eligibleLine{}
+	for _, line := range lines {
+		if len(products) > 0 && !products[line.ProductID] {
+			continue
+		}
+
+		eligible = append(eligible, &eligibleLine{
+			quantity:  line.Quantity,
+			unitPrice: line.UnitPrice,
+			total:     line.Total,
+		})
+	}
+
+	return eligible
+}
+
+func linesTotal(lines []*eligibleLine, currency string) models.Money {
+	sum := models.NewMoney(0, currency)
+	for _, line := range lines {
+		sum.Amount += line.total.Amount
+	}
+
+	return sum
+}
+
+func candidatesTotal(candidates []*candidate, currency string) models.Money {
+	sum := models.NewMoney(0, currency)
+	for _, candidate := range candidates {
+		sum.Amount += candidate.amount.Amount
+	}
+
+	return sum
+}
+
+func findPromotion(catalog []*models.Promotion, code string) *models.Promotion {
+	for _, promotion := range catalog {
+		if promotion.Code == code {
+			return promotion
+		}
+	}
+
+	return nil
+}
+
+func findPromotionByKey(catalog []*models.Promotion, code string, name string) *models.Promotion {
+	for _, promotion := range catalog {
+		if len(code) > 0 && promotion.Code == code {
+			return promotion
+		}
+
+		if len(code) == 0 && len(promotion.Code) == 0 && promotion.Name == name {
+			return promotion
+		}
+	}
+
+	return nil
+}
+
+func promotionKey(promotion *models.Promotion) string {
+	if len(promotion.Code) > 0 {
+		return promotion.Code
+	}
+
+	return promotion.Name
+}
@@ -0,0 +1,15 @@
+package rates
+
+import (
+	"context"
+	"errors"
+
+	"order/src/models"
+)
+
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+type ExchangeRateProvider interface {
+	// Rate returns how many units of quote one unit of base is worth.
+	Rate(ctx context.Context, base string, quote string) (*models.ExchangeRate, error)
+}
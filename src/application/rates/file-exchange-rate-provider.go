@@ -0,0 +1,119 @@
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"order/src/models"
+)
+
+const exchangeRateDecimals = 10
+
+type exchangeRatesFile struct {
+	Base      string            `json:"base"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+	Rates     map[string]string `json:"rates"`
+}
+
+// FileExchangeRateProvider serves rates from a JSON file holding the value of
+// one unit of a base currency in every other currency. The file is reloaded
+// whenever it changes on disk.
+type FileExchangeRateProvider struct {
+	path     string
+	mutex    sync.Mutex
+	modTime  time.Time
+	contents *exchangeRatesFile
+}
+
+func NewFileExchangeRateProvider(
+	path string,
+) *FileExchangeRateProvider {
+	return &FileExchangeRateProvider{
+		path: path,
+	}
+}
+
+func (p *FileExchangeRateProvider) Rate(ctx context.Context, base string, quote string) (*models.ExchangeRate, error) {
+	contents, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+
+	baseRate, err := contents.rate(base)
+	if err != nil {
+		return nil, err
+	}
+
+	quoteRate, err := contents.rate(quote)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := new(big.Rat).Quo(quoteRate, baseRate)
+
+	return &models.ExchangeRate{
+		Base:   base,
+		Quote:  quote,
+		Rate:   rate.FloatString(exchangeRateDecimals),
+		Source: fmt.Sprintf("file:%s", p.path),
+		At:     contents.UpdatedAt,
+	}, nil
+}
+
+func (p *FileExchangeRateProvider) load() (*exchangeRatesFile, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.contents != nil && info.ModTime().Equal(p.modTime) {
+		return p.contents, nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := &exchangeRatesFile{}
+	err = json.Unmarshal(data, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	contents.Base = strings.ToUpper(contents.Base)
+
+	p.contents = contents
+	p.modTime = info.ModTime()
+
+	return contents, nil
+}
+
+func (f *exchangeRatesFile) rate(currency string) (*big.Rat, error) {
+	if currency == f.Base {
+		return big.NewRat(1, 1), nil
+	}
+
+	value, ok := f.Rates[currency]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
+	}
+
+	rate, ok := new(big.Rat).SetString(value)
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid exchange rate for %s: %s", currency, value)
+	}
+
+	return rate, nil
+}
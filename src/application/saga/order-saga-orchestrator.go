@@ -0,0 +1,343 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"order/src/application/sensitive"
+	"order/src/models"
+	"order/src/nats/subjects"
+	"order/src/repositories/interfaces"
+
+	common_nats "github.com/JohnSalazar/microservices-go-common/nats"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	StepPayment      = "payment"
+	StepStoreBooking = "store_booking"
+	StepStorePayment = "store_payment"
+)
+
+type stepDefinition struct {
+	name                string
+	actionSubject       string
+	compensationSubject string
+}
+
+// checkoutSteps is the checkout flow in execution order. Every step names the
+// command that undoes it so a canceled order can be rolled back in reverse.
+var checkoutSteps = []stepDefinition{
+	{StepPayment, string(common_nats.PaymentCreate), string(common_nats.PaymentCancel)},
+	{StepStoreBooking, string(common_nats.StoreBook), string(subjects.StoreRelease)},
+	{StepStorePayment, string(common_nats.StorePayment), string(subjects.StorePaymentReverse)},
+}
+
+// OrderSagaOrchestrator persists where each order is in the checkout flow and
+// writes step commands and compensations to the outbox. It is called from the
+// order event handlers, so every change shares the command's transaction.
+type OrderSagaOrchestrator struct {
+	sagaRepository   interfaces.OrderSagaRepository
+	outboxRepository interfaces.OutboxRepository
+}
+
+func NewOrderSagaOrchestrator(
+	sagaRepository interfaces.OrderSagaRepository,
+	outboxRepository interfaces.OutboxRepository,
+) *OrderSagaOrchestrator {
+	return &OrderSagaOrchestrator{
+		sagaRepository:   sagaRepository,
+		outboxRepository: outboxRepository,
+	}
+}
+
+func (o *OrderSagaOrchestrator) Find(ctx context.Context, orderID primitive.ObjectID) (*models.OrderSaga, error) {
+	return o.sagaRepository.FindByOrderID(ctx, orderID)
+}
+
+// Start creates the saga for a new order and sends the first step.
+func (o *OrderSagaOrchestrator) Start(ctx context.Context, orderID primitive.ObjectID, data []byte) error {
+	now := time.Now().UTC()
+
+	saga := &models.OrderSaga{
+		OrderID:   orderID,
+		Status:    models.SagaRunning,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+
+	for _, definition := range checkoutSteps {
+		saga.Steps = append(saga.Steps, &models.SagaStep{
+			Name:                definition.name,
+			Status:              models.SagaStepPending,
+			ActionSubject:       definition.actionSubject,
+			CompensationSubject: definition.compensationSubject,
+		})
+	}
+
+	step := saga.Steps[0]
+	o.begin(saga, step, data, now)
+
+	err := o.sagaRepository.Create(ctx, saga)
+	if err != nil {
+		return err
+	}
+
+	return o.publish(ctx, orderID, step.ActionSubject, msgID(orderID, step.Name, step.Attempts), data)
+}
+
+// Advance completes every step before next and sends next. Orders created
+// before sagas existed have no saga; their command is published as before.
+func (o *OrderSagaOrchestrator) Advance(ctx context.Context, orderID primitive.ObjectID, next string, data []byte) error {
+	saga, err := o.sagaRepository.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	if saga == nil {
+		return o.publish(ctx, orderID, subjectOf(next), "", data)
+	}
+
+	step := saga.Step(next)
+	if step == nil {
+		return fmt.Errorf("order saga has no step %q", next)
+	}
+
+	if saga.Status != models.SagaRunning {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	for _, previous := range saga.Steps {
+		if previous == step {
+			break
+		}
+
+		if previous.Status != models.SagaStepCompleted {
+			previous.Status = models.SagaStepCompleted
+			previous.Error = ""
+			previous.CompletedAt = now
+		}
+	}
+
+	o.begin(saga, step, data, now)
+
+	err = o.sagaRepository.Update(ctx, saga)
+	if err != nil {
+		return err
+	}
+
+	return o.publish(ctx, orderID, step.ActionSubject, msgID(orderID, step.Name, step.Attempts), data)
+}
+
+// Complete marks a step as done. The saga completes with its last step.
+func (o *OrderSagaOrchestrator) Complete(ctx context.Context, orderID primitive.ObjectID, name string) error {
+	saga, err := o.sagaRepository.FindByOrderID(ctx, orderID)
+	if err != nil || saga == nil {
+		return err
+	}
+
+	step := saga.Step(name)
+	if step == nil || step.Status == models.SagaStepCompleted || saga.Status != models.SagaRunning {
+		return nil
+	}
+
+	step.Status = models.SagaStepCompleted
+	step.Error = ""
+	step.CompletedAt = time.Now().UTC()
+
+	if saga.Steps[len(saga.Steps)-1] == step {
+		saga.Status = models.SagaCompleted
+		saga.CurrentStep = ""
+	}
+
+	return o.sagaRepository.Update(ctx, saga)
+}
+
+// Progress records that the service running a started step reported it is
+// still working on it, e.g. a payment awaiting confirmation. Retry leaves such
+// a step alone instead of sending its command again.
+func (o *OrderSagaOrchestrator) Progress(ctx context.Context, orderID primitive.ObjectID, name string, progress string) error {
+	saga, err := o.sagaRepository.FindByOrderID(ctx, orderID)
+	if err != nil || saga == nil {
+		return err
+	}
+
+	step := saga.Step(name)
+	if step == nil || step.Status != models.SagaStepStarted || step.Progress == progress {
+		return nil
+	}
+
+	step.Progress = progress
+
+	return o.sagaRepository.Update(ctx, saga)
+}
+
+// Revise replaces the command a started step resends, e.g. after some of its
+// items were canceled, so Retry does not ask for them again.
+func (o *OrderSagaOrchestrator) Revise(ctx context.Context, orderID primitive.ObjectID, name string, data []byte) error {
+	saga, err := o.sagaRepository.FindByOrderID(ctx, orderID)
+	if err != nil || saga == nil {
+		return err
+	}
+
+	step := saga.Step(name)
+	if step == nil || step.Status != models.SagaStepStarted {
+		return nil
+	}
+
+	redacted, ok := sensitive.Redact(data)
+	step.ActionData = string(redacted)
+	step.Redacted = ok
+
+	return o.sagaRepository.Update(ctx, saga)
+}
+
+// Fail records that a step was rejected. The saga keeps running until the
+// order is canceled; a failed step left nothing behind, so Compensate skips it.
+func (o *OrderSagaOrchestrator) Fail(ctx context.Context, orderID primitive.ObjectID, name string, reason string) error {
+	saga, err := o.sagaRepository.FindByOrderID(ctx, orderID)
+	if err != nil || saga == nil {
+		return err
+	}
+
+	step := saga.Step(name)
+	if step == nil || saga.Status != models.SagaRunning {
+		return nil
+	}
+
+	step.Status = models.SagaStepFailed
+	step.Error = reason
+
+	return o.sagaRepository.Update(ctx, saga)
+}
+
+// Compensate sends the compensation of every step that was started and did
+// not fail, newest first. data holds the payload per step; steps without one get the order ID
+// and the reason. It returns false when the order has no saga.
+func (o *OrderSagaOrchestrator) Compensate(ctx context.Context, orderID primitive.ObjectID, reason string, data map[string][]byte) (bool, error) {
+	saga, err := o.sagaRepository.FindByOrderID(ctx, orderID)
+	if err != nil || saga == nil {
+		return false, err
+	}
+
+	if saga.Status == models.SagaCompensated {
+		return true, nil
+	}
+
+	now := time.Now().UTC()
+	for i := len(saga.Steps) - 1; i >= 0; i-- {
+		step := saga.Steps[i]
+		if step.Status == models.SagaStepPending || step.Status == models.SagaStepFailed || step.Status == models.SagaStepCompensated {
+			continue
+		}
+
+		payload, ok := data[step.Name]
+		if !ok {
+			payload, _ = json.Marshal(map[string]interface{}{
+				"orderId": orderID,
+				"reason":  reason,
+			})
+		}
+
+		err = o.publish(ctx, orderID, step.CompensationSubject, msgID(orderID, step.Name, 0), payload)
+		if err != nil {
+			return true, err
+		}
+
+		step.Status = models.SagaStepCompensated
+		step.CompensatedAt = now
+	}
+
+	saga.Status = models.SagaCompensated
+	saga.CurrentStep = ""
+	saga.Reason = reason
+
+	return true, o.sagaRepository.Update(ctx, saga)
+}
+
+// Retry sends the command of every started step whose last attempt is older
+// than before. Once a step has used maxAttempts, or its command was redacted
+// and cannot be sent again, the saga is marked stalled and left for an
+// operator; Retry then reports the stalled step.
+func (o *OrderSagaOrchestrator) Retry(ctx context.Context, saga *models.OrderSaga, before time.Time, maxAttempts uint) (*models.SagaStep, error) {
+	now := time.Now().UTC()
+
+	var stalled *models.SagaStep
+	for _, step := range saga.Steps {
+		if step.Status != models.SagaStepStarted || len(step.Progress) > 0 || !step.LastAttemptAt.Before(before) {
+			continue
+		}
+
+		if step.Attempts >= maxAttempts || step.Redacted {
+			stalled = step
+			break
+		}
+
+		step.Attempts++
+		step.LastAttemptAt = now
+
+		err := o.publish(ctx, saga.OrderID, step.ActionSubject, msgID(saga.OrderID, step.Name, step.Attempts), []byte(step.ActionData))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if stalled != nil {
+		saga.Status = models.SagaStalled
+		saga.Reason = fmt.Sprintf("step %s got no reply after %d attempts", stalled.Name, stalled.Attempts)
+		if stalled.Redacted {
+			saga.Reason = fmt.Sprintf("step %s got no reply and cannot be resent without card data", stalled.Name)
+		}
+	}
+
+	return stalled, o.sagaRepository.Update(ctx, saga)
+}
+
+func (o *OrderSagaOrchestrator) begin(saga *models.OrderSaga, step *models.SagaStep, data []byte, now time.Time) {
+	saga.CurrentStep = step.Name
+	step.Status = models.SagaStepStarted
+	// The command is kept to resend it, but never with card data.
+	redacted, ok := sensitive.Redact(data)
+	step.ActionData = string(redacted)
+	step.Redacted = ok
+	step.Error = ""
+	step.Progress = ""
+	step.Attempts++
+	step.StartedAt = now
+	step.LastAttemptAt = now
+}
+
+func (o *OrderSagaOrchestrator) publish(ctx context.Context, orderID primitive.ObjectID, subject string, ID string, data []byte) error {
+	message := &models.OutboxMessage{
+		OrderID: orderID,
+		Subject: subject,
+		MsgID:   ID,
+		Data:    string(data),
+	}
+
+	return o.outboxRepository.Add(ctx, message)
+}
+
+// msgID is the Nats-Msg-Id of a step command, so JetStream drops a copy that
+// is published twice. Attempt 0 is the step's compensation.
+func msgID(orderID primitive.ObjectID, name string, attempt uint) string {
+	if attempt == 0 {
+		return fmt.Sprintf("%s:%s:compensate", orderID.Hex(), name)
+	}
+
+	return fmt.Sprintf("%s:%s:%d", orderID.Hex(), name, attempt)
+}
+
+func subjectOf(name string) string {
+	for _, definition := range checkoutSteps {
+		if definition.name == name {
+			return definition.actionSubject
+		}
+	}
+
+	return name
+}
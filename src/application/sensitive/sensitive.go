@@ -0,0 +1,36 @@
+package sensitive
+
+import "encoding/json"
+
+// fields are payload keys carrying card data. They are only sent to the
+// payment service and must not be kept once the message has left or has
+// stopped being deliverable.
+var fields = []string{"cardNumber", "kid"}
+
+// Redact removes the card fields from a JSON object payload and reports
+// whether anything was removed. Other payloads are returned unchanged.
+func Redact(data []byte) ([]byte, bool) {
+	object := map[string]json.RawMessage{}
+	if json.Unmarshal(data, &object) != nil {
+		return data, false
+	}
+
+	redacted := false
+	for _, field := range fields {
+		if _, ok := object[field]; ok {
+			delete(object, field)
+			redacted = true
+		}
+	}
+
+	if !redacted {
+		return data, false
+	}
+
+	result, err := json.Marshal(object)
+	if err != nil {
+		return data, false
+	}
+
+	return result, true
+}
@@ -0,0 +1,110 @@
+package statemachine
+
+import (
+	"fmt"
+
+	common_models "github.com/JohnSalazar/microservices-go-common/models"
+)
+
+type InvalidTransitionError struct {
+	From common_models.Status
+	To   common_models.Status
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("invalid order status transition from %q to %q", e.From, e.To)
+}
+
+var orderTransitions = map[common_models.Status][]common_models.Status{
+	common_models.OrderCreated: {
+		common_models.SentForPaymentConfirmation,
+		common_models.AwaitingPaymentConfirmation,
+		common_models.PaymentConfirmed,
+		common_models.PaymentRejected,
+		common_models.PaymentCanceled,
+		common_models.OrderCanceled,
+	},
+	common_models.SentForPaymentConfirmation: {
+		common_models.AwaitingPaymentConfirmation,
+		common_models.PaymentConfirmed,
+		common_models.PaymentRejected,
+		common_models.PaymentCanceled,
+		common_models.OrderCanceled,
+	},
+	common_models.AwaitingPaymentConfirmation: {
+		common_models.PaymentConfirmed,
+		common_models.PaymentRejected,
+		common_models.PaymentCanceled,
+		common_models.OrderCanceled,
+	},
+	common_models.PaymentRejected: {
+		common_models.SentForPaymentConfirmation,
+		common_models.AwaitingPaymentConfirmation,
+		common_models.OrderCanceled,
+	},
+	common_models.PaymentConfirmed: {
+		common_models.PaymentCanceled,
+		common_models.OrderCanceled,
+	},
+	common_models.PaymentCanceled: {
+		common_models.OrderCanceled,
+	},
+	common_models.OrderCanceled: {},
+}
+
+var customerCancelable = map[common_models.Status]bool{
+	common_models.OrderCreated:                true,
+	common_models.SentForPaymentConfirmation:  true,
+	common_models.AwaitingPaymentConfirmation: true,
+	common_models.PaymentRejected:             true,
+}
+
+// Items can be dropped until fulfillment starts; once payment is confirmed
+// the difference is refunded instead of adjusted.
+var itemsCancelable = map[common_models.Status]bool{
+	common_models.OrderCreated:                true,
+	common_models.SentForPaymentConfirmation:  true,
+	common_models.AwaitingPaymentConfirmation: true,
+	common_models.PaymentConfirmed:            true,
+}
+
+// Orders can be amended while payment has not been confirmed yet.
+var amendable = map[common_models.Status]bool{
+	common_models.OrderCreated:                true,
+	common_models.SentForPaymentConfirmation:  true,
+	common_models.AwaitingPaymentConfirmation: true,
+}
+
+func AllowedTransitions(from common_models.Status) []common_models.Status {
+	return orderTransitions[from]
+}
+
+func CanTransition(from common_models.Status, to common_models.Status) bool {
+	for _, status := range orderTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Transition(from common_models.Status, to common_models.Status) error {
+	if !CanTransition(from, to) {
+		return &InvalidTransitionError{From: from, To: to}
+	}
+
+	return nil
+}
+
+func CanCustomerCancel(status common_models.Status) bool {
+	return customerCancelable[status] && CanTransition(status, common_models.OrderCanceled)
+}
+
+func CanCancelItems(status common_models.Status) bool {
+	return itemsCancelable[status]
+}
+
+func CanAmend(status common_models.Status) bool {
+	return amendable[status]
+}
@@ -0,0 +1,73 @@
+package statemachine
+
+import (
+	"errors"
+	"testing"
+
+	common_models "github.com/JohnSalazar/microservices-go-common/models"
+)
+
+func TestTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    common_models.Status
+		to      common_models.Status
+		allowed bool
+	}{
+		{"created to sent for payment", common_models.OrderCreated, common_models.SentForPaymentConfirmation, true},
+		{"created to payment confirmed", common_models.OrderCreated, common_models.PaymentConfirmed, true},
+		{"created to canceled", common_models.OrderCreated, common_models.OrderCanceled, true},
+		{"awaiting payment to rejected", common_models.AwaitingPaymentConfirmation, common_models.PaymentRejected, true},
+		{"rejected to sent for payment", common_models.PaymentRejected, common_models.SentForPaymentConfirmation, true},
+		{"rejected to confirmed", common_models.PaymentRejected, common_models.PaymentConfirmed, false},
+		{"confirmed to canceled", common_models.PaymentConfirmed, common_models.OrderCanceled, true},
+		{"confirmed to created", common_models.PaymentConfirmed, common_models.OrderCreated, false},
+		{"payment canceled to confirmed", common_models.PaymentCanceled, common_models.PaymentConfirmed, false},
+		{"canceled to created", common_models.OrderCanceled, common_models.OrderCreated, false},
+		{"canceled to confirmed", common_models.OrderCanceled, common_models.PaymentConfirmed, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Transition(test.from, test.to)
+
+			if test.allowed && err != nil {
+				t.Fatalf("expected transition to be allowed, got %v", err)
+			}
+
+			if !test.allowed {
+				var transitionError *InvalidTransitionError
+				if !errors.As(err, &transitionError) {
+					t.Fatalf("expected InvalidTransitionError, got %v", err)
+				}
+
+				if transitionError.From != test.from || transitionError.To != test.to {
+					t.Fatalf("expected error for %v -> %v, got %v -> %v", test.from, test.to, transitionError.From, transitionError.To)
+				}
+			}
+		})
+	}
+}
+
+func TestCanCustomerCancel(t *testing.T) {
+	tests := []struct {
+		status     common_models.Status
+		cancelable bool
+	}{
+		{common_models.OrderCreated, true},
+		{common_models.SentForPaymentConfirmation, true},
+		{common_models.AwaitingPaymentConfirmation, true},
+		{common_models.PaymentRejected, true},
+		{common_models.PaymentConfirmed, false},
+		{common_models.PaymentCanceled, false},
+		{common_models.OrderCanceled, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.status.String(), func(t *testing.T) {
+			if got := CanCustomerCancel(test.status); got != test.cancelable {
+				t.Fatalf("expected %t, got %t", test.cancelable, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package statemachine
+
+import (
+	"fmt"
+
+	"order/src/models"
+
+	common_models "github.com/JohnSalazar/microservices-go-common/models"
+)
+
+type InvalidReturnTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *InvalidReturnTransitionError) Error() string {
+	return fmt.Sprintf("invalid return status transition from %q to %q", e.From, e.To)
+}
+
+var returnTransitions = map[string][]string{
+	models.ReturnRequested: {models.ReturnApproved, models.ReturnRejected},
+	models.ReturnApproved:  {models.ReturnReceived, models.ReturnRejected},
+	models.ReturnReceived:  {models.ReturnRefunded},
+}
+
+func ReturnTransition(from string, to string) error {
+	for _, status := range returnTransitions[from] {
+		if status == to {
+			return nil
+		}
+	}
+
+	return &InvalidReturnTransitionError{From: from, To: to}
+}
+
+// CanReturn reports whether items of an order can be sent back: the order
+// has been paid for and at least one shipment reached the customer. Orders
+// booked before shipments were tracked only need the stores to have booked
+// the goods.
+func CanReturn(order *models.Order) bool {
+	if common_models.Status(order.Status) != common_models.PaymentConfirmed {
+		return false
+	}
+
+	if len(order.Shipments) == 0 {
+		return len(order.Stores) > 0
+	}
+
+	fulfillment := FulfillmentStatus(order.Shipments)
+
+	return fulfillment == models.FulfillmentDelivered || fulfillment == models.FulfillmentPartiallyDelivered
+}
@@ -0,0 +1,42 @@
+package statemachine
+
+import (
+	"errors"
+	"testing"
+
+	"order/src/models"
+)
+
+func TestReturnTransition(t *testing.T) {
+	tests := []struct {
+		from    string
+		to      string
+		allowed bool
+	}{
+		{models.ReturnRequested, models.ReturnApproved, true},
+		{models.ReturnRequested, models.ReturnRejected, true},
+		{models.ReturnRequested, models.ReturnReceived, false},
+		{models.ReturnApproved, models.ReturnReceived, true},
+		{models.ReturnApproved, models.ReturnRejected, true},
+		{models.ReturnApproved, models.ReturnRefunded, false},
+		{models.ReturnReceived, models.ReturnRefunded, true},
+		{models.ReturnReceived, models.ReturnRejected, false},
+		{models.ReturnRejected, models.ReturnApproved, false},
+		{models.ReturnRefunded, models.ReturnReceived, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.from+" to "+test.to, func(t *testing.T) {
+			err := ReturnTransition(test.from, test.to)
+
+			if test.allowed && err != nil {
+				t.Fatalf("expected transition to be allowed, got %v", err)
+			}
+
+			var transitionError *InvalidReturnTransitionError
+			if !test.allowed && !errors.As(err, &transitionError) {
+				t.Fatalf("expected InvalidReturnTransitionError, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package statemachine
+
+import (
+	"fmt"
+
+	"order/src/models"
+
+	"github.com/google/uuid"
+)
+
+type InvalidShipmentTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *InvalidShipmentTransitionError) Error() string {
+	return fmt.Sprintf("invalid shipment status transition from %q to %q", e.From, e.To)
+}
+
+// Shipments only move forward; a store may skip steps it does not report
+// (e.g. straight from pending to shipped).
+var shipmentSteps = map[string]int{
+	models.ShipmentPending:   0,
+	models.ShipmentPacked:    1,
+	models.ShipmentShipped:   2,
+	models.ShipmentDelivered: 3,
+}
+
+func ShipmentTransition(from string, to string) error {
+	fromStep, ok := shipmentSteps[from]
+	if !ok {
+		return &InvalidShipmentTransitionError{From: from, To: to}
+	}
+
+	toStep, ok := shipmentSteps[to]
+	if !ok || toStep <= fromStep {
+		return &InvalidShipmentTransitionError{From: from, To: to}
+	}
+
+	return nil
+}
+
+// FulfillmentStatus derives the overall fulfillment state of an order from
+// its shipments.
+func FulfillmentStatus(shipments []*models.Shipment) string {
+	if len(shipments) == 0 {
+		return models.FulfillmentUnfulfilled
+	}
+
+	shipped, delivered := 0, 0
+	for _, shipment := range shipments {
+		switch shipment.Status {
+		case models.ShipmentShipped:
+			shipped++
+		case models.ShipmentDelivered:
+			shipped++
+			delivered++
+		}
+	}
+
+	switch {
+	case delivered == len(shipments):
+		return models.FulfillmentDelivered
+	case delivered > 0:
+		return models.FulfillmentPartiallyDelivered
+	case shipped == len(shipments):
+		return models.FulfillmentShipped
+	case shipped > 0:
+		return models.FulfillmentPartiallyShipped
+	}
+
+	return models.FulfillmentUnfulfilled
+}
+
+// ShippedProduct returns the first of productIDs whose shipment has already
+// left the store. Such items can no longer be canceled, only returned.
+func ShippedProduct(shipments []*models.Shipment, productIDs []uuid.UUID) (uuid.UUID, bool) {
+	for _, shipment := range shipments {
+		if shipment.Status == models.ShipmentPending || shipment.Status == models.ShipmentPacked {
+			continue
+		}
+
+		for _, shipped := range shipment.ProductIDs {
+			for _, productID := range productIDs {
+				if shipped == productID {
+					return productID, true
+				}
+			}
+		}
+	}
+
+	return uuid.Nil, false
+}
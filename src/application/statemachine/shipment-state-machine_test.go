@@ -0,0 +1,106 @@
+package statemachine
+
+import (
+	"errors"
+	"testing"
+
+	"order/src/models"
+
+	"github.com/google/uuid"
+)
+
+func TestShipmentTransition(t *testing.T) {
+	tests := []struct {
+		from    string
+		to      string
+		allowed bool
+	}{
+		{models.ShipmentPending, models.ShipmentPacked, true},
+		{models.ShipmentPending, models.ShipmentShipped, true},
+		{models.ShipmentPending, models.ShipmentDelivered, true},
+		{models.ShipmentPacked, models.ShipmentShipped, true},
+		{models.ShipmentShipped, models.ShipmentDelivered, true},
+		{models.ShipmentPending, models.ShipmentPending, false},
+		{models.ShipmentPacked, models.ShipmentPending, false},
+		{models.ShipmentShipped, models.ShipmentPacked, false},
+		{models.ShipmentDelivered, models.ShipmentShipped, false},
+		{models.ShipmentPending, "lost", false},
+		{"lost", models.ShipmentShipped, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.from+" to "+test.to, func(t *testing.T) {
+			err := ShipmentTransition(test.from, test.to)
+
+			if test.allowed && err != nil {
+				t.Fatalf("expected transition to be allowed, got %v", err)
+			}
+
+			var transitionError *InvalidShipmentTransitionError
+			if !test.allowed && !errors.As(err, &transitionError) {
+				t.Fatalf("expected InvalidShipmentTransitionError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFulfillmentStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		expected string
+	}{
+		{"no shipments", nil, models.FulfillmentUnfulfilled},
+		{"all pending", []string{models.ShipmentPending, models.ShipmentPacked}, models.FulfillmentUnfulfilled},
+		{"one shipped", []string{models.ShipmentShipped, models.ShipmentPending}, models.FulfillmentPartiallyShipped},
+		{"all shipped", []string{models.ShipmentShipped, models.ShipmentShipped}, models.FulfillmentShipped},
+		{"one delivered", []string{models.ShipmentDelivered, models.ShipmentPacked}, models.FulfillmentPartiallyDelivered},
+		{"delivered and shipped", []string{models.ShipmentDelivered, models.ShipmentShipped}, models.FulfillmentPartiallyDelivered},
+		{"all delivered", []string{models.ShipmentDelivered, models.ShipmentDelivered}, models.FulfillmentDelivered},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shipments := []*models.Shipment{}
+			for _, status := range test.statuses {
+				shipments = append(shipments, &models.Shipment{Status: status})
+			}
+
+			status := FulfillmentStatus(shipments)
+			if status != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, status)
+			}
+		})
+	}
+}
+
+func TestShippedProduct(t *testing.T) {
+	packed, shipped, delivered := uuid.New(), uuid.New(), uuid.New()
+	shipments := []*models.Shipment{
+		{Status: models.ShipmentPacked, ProductIDs: []uuid.UUID{packed}},
+		{Status: models.ShipmentShipped, ProductIDs: []uuid.UUID{shipped}},
+		{Status: models.ShipmentDelivered, ProductIDs: []uuid.UUID{delivered}},
+	}
+
+	tests := []struct {
+		name       string
+		productIDs []uuid.UUID
+		expected   uuid.UUID
+		found      bool
+	}{
+		{"packed items can be canceled", []uuid.UUID{packed}, uuid.Nil, false},
+		{"shipped items cannot", []uuid.UUID{packed, shipped}, shipped, true},
+		{"delivered items cannot", []uuid.UUID{delivered}, delivered, true},
+		{"items without a shipment can", []uuid.UUID{uuid.New()}, uuid.Nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			productID, found := ShippedProduct(shipments, test.productIDs)
+
+			if found != test.found || productID != test.expected {
+				t.Fatalf("expected %s %t, got %s %t", test.expected, test.found, productID, found)
+			}
+		})
+	}
+}
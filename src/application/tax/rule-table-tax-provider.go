@@ -0,0 +1,88 @@
+package tax
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"order/src/models"
+	"order/src/settings"
+)
+
+type taxRule struct {
+	name string
+	rate *big.Rat
+	text string
+}
+
+// RuleTableTaxProvider looks rates up in a static table keyed by region and
+// product category. Rules with an empty category apply to every product of
+// the region that has no rule of its own; several rules for the same key are
+// all charged (e.g. a state and a federal tax).
+type RuleTableTaxProvider struct {
+	rules map[string]map[string][]*taxRule
+}
+
+func NewRuleTableTaxProvider(
+	settings *settings.TaxSettings,
+) (*RuleTableTaxProvider, error) {
+	rules := map[string]map[string][]*taxRule{}
+
+	for _, rule := range settings.Rules {
+		rate, ok := new(big.Rat).SetString(rule.Rate)
+		if !ok || rate.Sign() < 0 {
+			return nil, fmt.Errorf("invalid tax rate for %s/%s: %s", rule.Region, rule.Category, rule.Rate)
+		}
+
+		region := strings.ToUpper(rule.Region)
+		category := strings.ToLower(rule.Category)
+
+		if rules[region] == nil {
+			rules[region] = map[string][]*taxRule{}
+		}
+
+		rules[region][category] = append(rules[region][category], &taxRule{
+			name: rule.Name,
+			rate: rate,
+			text: rule.Rate,
+		})
+	}
+
+	return &RuleTableTaxProvider{
+		rules: rules,
+	}, nil
+}
+
+func (p *RuleTableTaxProvider) Calculate(ctx context.Context, region string, lines []*models.OrderLine) ([]*models.TaxLine, error) {
+	region = strings.ToUpper(region)
+
+	categories, ok := p.rules[region]
+	if !ok {
+		return nil, nil
+	}
+
+	taxLines := []*models.TaxLine{}
+	for _, line := range lines {
+		category := strings.ToLower(line.Category)
+
+		rules, ok := categories[category]
+		if !ok {
+			rules = categories[""]
+		}
+
+		for _, rule := range rules {
+			taxLines = append(taxLines, &models.TaxLine{
+				ProductID: line.ProductID,
+				Region:    region,
+				Category:  line.Category,
+				Name:      rule.name,
+				Rate:      rule.text,
+				Taxable:   line.Total,
+				Amount:    line.Total.MultiplyRate(rule.rate),
+			})
+		}
+	}
+
+	return taxLines, nil
+}
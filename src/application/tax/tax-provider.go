@@ -0,0 +1,27 @@
+package tax
+
+import (
+	"context"
+
+	"order/src/models"
+)
+
+type TaxProvider interface {
+	// Calculate returns the tax lines owed on lines when shipped to region.
+	// An empty result means the order is not taxed.
+	Calculate(ctx context.Context, region string, lines []*models.OrderLine) ([]*models.TaxLine, error)
+}
+
+func Total(taxLines []*models.TaxLine, currency string) (models.Money, error) {
+	total := models.NewMoney(0, currency)
+
+	for _, taxLine := range taxLines {
+		var err error
+		total, err = total.Add(taxLine.Amount)
+		if err != nil {
+			return models.Money{}, err
+		}
+	}
+
+	return total, nil
+}
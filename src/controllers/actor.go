@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	customerActor = "customer"
+	adminActor    = "admin"
+)
+
+func actorSource(c *gin.Context, actor string) string {
+	ID, _ := c.Get("user")
+
+	return fmt.Sprintf("%s:%v", actor, ID)
+}
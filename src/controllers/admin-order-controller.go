@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"order/src/application/commands"
+	"order/src/application/eventstore"
+	"order/src/application/saga"
+	"order/src/dtos"
+	"order/src/models"
+	"order/src/repositories/interfaces"
+
+	"github.com/JohnSalazar/microservices-go-common/helpers"
+	"github.com/JohnSalazar/microservices-go-common/httputil"
+	trace "github.com/JohnSalazar/microservices-go-common/trace/otel"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AdminOrderController struct {
+	orderRepository     interfaces.OrderRepository
+	orderCommandHandler *commands.OrderCommandHandler
+	orderEventStore     *eventstore.OrderEventStore
+	orderSaga           *saga.OrderSagaOrchestrator
+}
+
+func NewAdminOrderController(
+	orderRepository interfaces.OrderRepository,
+	orderCommandHandler *commands.OrderCommandHandler,
+	orderEventStore *eventstore.OrderEventStore,
+	orderSaga *saga.OrderSagaOrchestrator,
+) *AdminOrderController {
+	return &AdminOrderController{
+		orderRepository:     orderRepository,
+		orderCommandHandler: orderCommandHandler,
+		orderEventStore:     orderEventStore,
+		orderSaga:           orderSaga,
+	}
+}
+
+func (admin *AdminOrderController) Search(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "AdminOrderController.Search")
+	defer span.End()
+
+	queryOptions, err := parseOrderQueryOptions(c)
+	if err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if customerID := c.Query("customerId"); len(customerID) > 0 {
+		if !helpers.IsValidID(customerID) {
+			httputil.NewResponseError(c, http.StatusBadRequest, "invalid customerId")
+			return
+		}
+		queryOptions.CustomerID = helpers.StringToID(customerID)
+	}
+
+	if productID := c.Query("productId"); len(productID) > 0 {
+		ID, err := uuid.Parse(productID)
+		if err != nil {
+			httputil.NewResponseError(c, http.StatusBadRequest, "invalid productId")
+			return
+		}
+		queryOptions.ProductID = ID
+	}
+
+	page, err := admin.orderRepository.Search(c.Request.Context(), queryOptions)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidCursor) || errors.Is(err, models.ErrInvalidSortField) {
+			httputil.NewResponseError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		httputil.NewResponseError(c, http.StatusBadRequest, "orders get error")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (admin *AdminOrderController) GetById(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "AdminOrderController.GetById")
+	defer span.End()
+
+	orderModel, ok := admin.order(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, orderModel)
+}
+
+func (admin *AdminOrderController) GetEvents(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "AdminOrderController.GetEvents")
+	defer span.End()
+
+	ID, ok := admin.orderID(c)
+	if !ok {
+		return
+	}
+
+	storedEvents, err := admin.orderEventStore.Events(c.Request.Context(), ID)
+	if err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, "order events get error")
+		return
+	}
+
+	c.JSON(http.StatusOK, storedEvents)
+}
+
+func (admin *AdminOrderController) GetSaga(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "AdminOrderController.GetSaga")
+	defer span.End()
+
+	ID, ok := admin.orderID(c)
+	if !ok {
+		return
+	}
+
+	orderSaga, err := admin.orderSaga.Find(c.Request.Context(), ID)
+	if err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, "order saga get error")
+		return
+	}
+
+	if orderSaga == nil {
+		httputil.NewResponseError(c, http.StatusNotFound, "order saga not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, orderSaga)
+}
+
+func (admin *AdminOrderController) Replay(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "AdminOrderController.Replay")
+	defer span.End()
+
+	ID, ok := admin.orderID(c)
+	if !ok {
+		return
+	}
+
+	at, err := parseQueryTime(c.Query("at"), true)
+	if err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orderModel, err := admin.orderEventStore.LoadAt(c.Request.Context(), ID, at)
+	if errors.Is(err, eventstore.ErrOrderNotFound) {
+		httputil.NewResponseError(c, http.StatusNotFound, "order not found")
+		return
+	}
+
+	if err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, "order replay error")
+		return
+	}
+
+	c.JSON(http.StatusOK, orderModel)
+}
+
+func (admin *AdminOrderController) UpdateStatus(c *gin.Context) {
+	ctx, span := trace.NewSpan(c.Request.Context(), "AdminOrderController.UpdateStatus")
+	defer span.End()
+
+	orderModel, ok := admin.order(c)
+	if !ok {
+		return
+	}
+
+	orderDto := &dtos.UpdateStatusOrder{}
+	if err := c.ShouldBindJSON(orderDto); err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, "invalid status")
+		return
+	}
+
+	if orderDto.StatusAt.IsZero() {
+		orderDto.StatusAt = time.Now().UTC()
+	}
+
+	command := &commands.UpdateStatusOrderCommand{
+		ID:       orderModel.ID,
+		Status:   orderDto.Status,
+		StatusAt: orderDto.StatusAt,
+		Force:    true,
+		Source:   actorSource(c, adminActor),
+	}
+
+	err := admin.orderCommandHandler.UpdateStatusOrderCommandHandler(ctx, command)
+	if err != nil {
+		commandError(c, err)
+		return
+	}
+
+	httputil.NewResponseSuccess(c, http.StatusOK, "order status updated")
+}
+
+func (admin *AdminOrderController) GetReturns(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "AdminOrderController.GetReturns")
+	defer span.End()
+
+	orderModel, ok := admin.order(c)
+	if !ok {
+		return
+	}
+
+	returns := orderModel.Returns
+	if returns == nil {
+		returns = []*models.ReturnRequest{}
+	}
+
+	c.JSON(http.StatusOK, returns)
+}
+
+func (admin *AdminOrderController) UpdateReturn(c *gin.Context) {
+	ctx, span := trace.NewSpan(c.Request.Context(), "AdminOrderController.UpdateReturn")
+	defer span.End()
+
+	orderModel, ok := admin.order(c)
+	if !ok {
+		return
+	}
+
+	returnID := c.Param("returnId")
+	if !helpers.IsValidID(returnID) {
+		httputil.NewResponseError(c, http.StatusBadRequest, "invalid returnId")
+		return
+	}
+
+	returnDto := &dtos.UpdateReturn{}
+	if err := c.ShouldBindJSON(returnDto); err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, "invalid return")
+		return
+	}
+
+	command := &commands.UpdateReturnCommand{
+		ID:       orderModel.ID,
+		ReturnID: helpers.StringToID(returnID),
+		Status:   returnDto.Status,
+		Note:     returnDto.Note,
+		Refund:   returnDto.Refund,
+		Source:   actorSource(c, adminActor),
+	}
+
+	err := admin.orderCommandHandler.UpdateReturnCommandHandler(ctx, command)
+	if err != nil {
+		commandError(c, err)
+		return
+	}
+
+	httputil.NewResponseSuccess(c, http.StatusOK, "return updated")
+}
+
+func (admin *AdminOrderController) orderID(c *gin.Context) (primitive.ObjectID, bool) {
+	ID := c.Param("id")
+	if !helpers.IsValidID(ID) {
+		httputil.NewResponseError(c, http.StatusBadRequest, "invalid orderId")
+		return primitive.NilObjectID, false
+	}
+
+	return helpers.StringToID(ID), true
+}
+
+func (admin *AdminOrderController) order(c *gin.Context) (*models.Order, bool) {
+	ID, ok := admin.orderID(c)
+	if !ok {
+		return nil, false
+	}
+
+	orderModel, err := admin.orderRepository.FindByID(c.Request.Context(), ID)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		httputil.NewResponseError(c, http.StatusBadRequest, "order get error")
+		return nil, false
+	}
+
+	if orderModel == nil {
+		httputil.NewResponseError(c, http.StatusNotFound, "order not found")
+		return nil, false
+	}
+
+	return orderModel, true
+}
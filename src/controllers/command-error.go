@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"order/src/application/commands"
+	"order/src/application/pricing"
+	"order/src/application/statemachine"
+
+	"github.com/JohnSalazar/microservices-go-common/httputil"
+	"github.com/gin-gonic/gin"
+)
+
+func commandError(c *gin.Context, err error) {
+	var validationError *commands.ValidationError
+	var transitionError *statemachine.InvalidTransitionError
+	var returnTransitionError *statemachine.InvalidReturnTransitionError
+	var shipmentTransitionError *statemachine.InvalidShipmentTransitionError
+	var totalMismatchError *pricing.TotalMismatchError
+
+	switch {
+	case errors.As(err, &validationError):
+		httputil.NewResponseError(c, http.StatusUnprocessableEntity, validationError.Errors)
+	case errors.As(err, &totalMismatchError):
+		httputil.NewResponseError(c, http.StatusUnprocessableEntity, err.Error())
+	case errors.As(err, &transitionError):
+		httputil.NewResponseError(c, http.StatusConflict, err.Error())
+	case errors.As(err, &returnTransitionError):
+		httputil.NewResponseError(c, http.StatusConflict, err.Error())
+	case errors.As(err, &shipmentTransitionError):
+		httputil.NewResponseError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, commands.ErrOrderAlreadyExists):
+		httputil.NewResponseError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, commands.ErrItemsNotCancelable):
+		httputil.NewResponseError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, commands.ErrItemsShipped):
+		httputil.NewResponseError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, commands.ErrOrderNotReturnable):
+		httputil.NewResponseError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, commands.ErrOrderNotAmendable):
+		httputil.NewResponseError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, commands.ErrOrderNotShippable):
+		httputil.NewResponseError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, commands.ErrVersionConflict):
+		httputil.NewResponseError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, commands.ErrReturnNotFound):
+		httputil.NewResponseError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, commands.ErrShipmentNotFound):
+		httputil.NewResponseError(c, http.StatusNotFound, err.Error())
+	default:
+		httputil.NewResponseError(c, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"order/src/dtos"
+	"order/src/nats/deadletter"
+
+	"github.com/JohnSalazar/microservices-go-common/httputil"
+	trace "github.com/JohnSalazar/microservices-go-common/trace/otel"
+	"github.com/gin-gonic/gin"
+)
+
+const deadLetterPageSize = 50
+
+type DeadLetterController struct {
+	deadLetterQueue *deadletter.DeadLetterQueue
+}
+
+func NewDeadLetterController(
+	deadLetterQueue *deadletter.DeadLetterQueue,
+) *DeadLetterController {
+	return &DeadLetterController{
+		deadLetterQueue: deadLetterQueue,
+	}
+}
+
+func (dlq *DeadLetterController) GetAll(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "DeadLetterController.GetAll")
+	defer span.End()
+
+	from, err := strconv.ParseUint(c.DefaultQuery("from", "0"), 10, 64)
+	if err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, "invalid from")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(deadLetterPageSize)))
+	if err != nil || limit <= 0 {
+		httputil.NewResponseError(c, http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	if limit > deadLetterPageSize {
+		limit = deadLetterPageSize
+	}
+
+	deadLetters, err := dlq.deadLetterQueue.List(from, limit)
+	if err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, "dead letters get error")
+		return
+	}
+
+	c.JSON(http.StatusOK, deadLetters)
+}
+
+func (dlq *DeadLetterController) GetBySequence(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "DeadLetterController.GetBySequence")
+	defer span.End()
+
+	seq, ok := dlq.sequence(c)
+	if !ok {
+		return
+	}
+
+	deadLetter, err := dlq.deadLetterQueue.Get(seq)
+	if err != nil {
+		dlq.deadLetterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deadLetter)
+}
+
+func (dlq *DeadLetterController) Resubmit(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "DeadLetterController.Resubmit")
+	defer span.End()
+
+	seq, ok := dlq.sequence(c)
+	if !ok {
+		return
+	}
+
+	resubmitDto := &dtos.ResubmitDeadLetter{}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(resubmitDto); err != nil {
+			httputil.NewResponseError(c, http.StatusBadRequest, "invalid dead letter data")
+			return
+		}
+	}
+
+	err := dlq.deadLetterQueue.Resubmit(seq, resubmitDto.Data)
+	if err != nil {
+		dlq.deadLetterError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (dlq *DeadLetterController) Replay(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "DeadLetterController.Replay")
+	defer span.End()
+
+	replayDto := &dtos.ReplayDeadLetters{}
+	if err := c.ShouldBindJSON(replayDto); err != nil || replayDto.To < replayDto.From {
+		httputil.NewResponseError(c, http.StatusBadRequest, "invalid replay range")
+		return
+	}
+
+	// A request replays at most one page; "to" tells the caller where the
+	// next one starts.
+	if replayDto.To-replayDto.From >= deadLetterPageSize {
+		replayDto.To = replayDto.From + deadLetterPageSize - 1
+	}
+
+	replayed, err := dlq.deadLetterQueue.Replay(replayDto.From, replayDto.To, replayDto.Subject)
+	if err != nil {
+		dlq.deadLetterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"replayed": replayed, "to": replayDto.To})
+}
+
+func (dlq *DeadLetterController) Discard(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "DeadLetterController.Discard")
+	defer span.End()
+
+	seq, ok := dlq.sequence(c)
+	if !ok {
+		return
+	}
+
+	err := dlq.deadLetterQueue.Discard(seq)
+	if err != nil {
+		dlq.deadLetterError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (dlq *DeadLetterController) sequence(c *gin.Context) (uint64, bool) {
+	seq, err := strconv.ParseUint(c.Param("seq"), 10, 64)
+	if err != nil || seq == 0 {
+		httputil.NewResponseError(c, http.StatusBadRequest, "invalid sequence")
+		return 0, false
+	}
+
+	return seq, true
+}
+
+func (dlq *DeadLetterController) deadLetterError(c *gin.Context, err error) {
+	var notReplayableError *deadletter.NotReplayableError
+
+	switch {
+	case errors.Is(err, deadletter.ErrDeadLetterNotFound):
+		httputil.NewResponseError(c, http.StatusNotFound, err.Error())
+	case errors.As(err, &notReplayableError):
+		httputil.NewResponseError(c, http.StatusUnprocessableEntity, err.Error())
+	default:
+		httputil.NewResponseError(c, http.StatusBadRequest, err.Error())
+	}
+}
@@ -1,25 +1,38 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
+	"order/src/application/commands"
+	"order/src/application/statemachine"
+	"order/src/models"
 	"order/src/repositories/interfaces"
 
 	"github.com/JohnSalazar/microservices-go-common/helpers"
 	"github.com/JohnSalazar/microservices-go-common/httputil"
+	common_models "github.com/JohnSalazar/microservices-go-common/models"
 	trace "github.com/JohnSalazar/microservices-go-common/trace/otel"
+	"github.com/gin-contrib/location"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type OrderController struct {
-	orderRepository interfaces.OrderRepository
+	orderRepository     interfaces.OrderRepository
+	orderCommandHandler *commands.OrderCommandHandler
 }
 
 func NewOrderController(
 	orderRepository interfaces.OrderRepository,
+	orderCommandHandler *commands.OrderCommandHandler,
 ) *OrderController {
 	return &OrderController{
-		orderRepository: orderRepository,
+		orderRepository:     orderRepository,
+		orderCommandHandler: orderCommandHandler,
 	}
 }
 
@@ -27,46 +40,339 @@ func (order *OrderController) GetAll(c *gin.Context) {
 	_, span := trace.NewSpan(c.Request.Context(), "OrderController.GetAll")
 	defer span.End()
 
-	ID, customerIDOk := c.Get("user")
-	if !customerIDOk {
-		httputil.NewResponseError(c, http.StatusForbidden, "invalid customer")
+	customerID, ok := order.customerID(c)
+	if !ok {
 		return
 	}
 
-	isID := helpers.IsValidID(ID.(string))
-	if !isID {
-		httputil.NewResponseError(c, http.StatusBadRequest, "invalid customerId")
+	queryOptions, err := parseOrderQueryOptions(c)
+	if err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	customerID := helpers.StringToID(ID.(string))
-
-	orders, err := order.orderRepository.GetAll(c.Request.Context(), customerID)
+	page, err := order.orderRepository.GetAll(c.Request.Context(), customerID, queryOptions)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidCursor) || errors.Is(err, models.ErrInvalidSortField) {
+			httputil.NewResponseError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		httputil.NewResponseError(c, http.StatusBadRequest, "orders get error")
 		return
 	}
 
-	c.JSON(http.StatusOK, orders)
+	c.JSON(http.StatusOK, page)
 }
 
 func (order *OrderController) GetById(c *gin.Context) {
 	_, span := trace.NewSpan(c.Request.Context(), "OrderController.GetById")
 	defer span.End()
 
-	ID, customerIDOk := c.Get("user")
-	if !customerIDOk {
+	customerID, ok := order.customerID(c)
+	if !ok {
+		return
+	}
+
+	orderModel, ok := order.customerOrder(c, customerID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, orderModel)
+}
+
+func (order *OrderController) Amend(c *gin.Context) {
+	ctx, span := trace.NewSpan(c.Request.Context(), "OrderController.Amend")
+	defer span.End()
+
+	customerID, ok := order.customerID(c)
+	if !ok {
+		return
+	}
+
+	orderModel, ok := order.customerOrder(c, customerID)
+	if !ok {
+		return
+	}
+
+	if !statemachine.CanAmend(common_models.Status(orderModel.Status)) {
+		httputil.NewResponseError(c, http.StatusConflict,
+			fmt.Sprintf("order cannot be amended: %s", common_models.Status(orderModel.Status)))
+		return
+	}
+
+	command := &commands.AmendOrderCommand{}
+	if err := c.ShouldBindJSON(command); err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	command.ID = orderModel.ID
+	command.Source = actorSource(c, customerActor)
+
+	err := order.orderCommandHandler.AmendOrderCommandHandler(ctx, command)
+	if err != nil {
+		commandError(c, err)
+		return
+	}
+
+	result, err := order.orderRepository.FindByID(ctx, orderModel.ID)
+	if err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, "order get error")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (order *OrderController) Cancel(c *gin.Context) {
+	ctx, span := trace.NewSpan(c.Request.Context(), "OrderController.Cancel")
+	defer span.End()
+
+	customerID, ok := order.customerID(c)
+	if !ok {
+		return
+	}
+
+	orderModel, ok := order.customerOrder(c, customerID)
+	if !ok {
+		return
+	}
+
+	if !statemachine.CanCustomerCancel(common_models.Status(orderModel.Status)) {
+		httputil.NewResponseError(c, http.StatusConflict,
+			fmt.Sprintf("order cannot be canceled: %s", common_models.Status(orderModel.Status)))
+		return
+	}
+
+	command := &commands.UpdateStatusOrderCommand{
+		ID:       orderModel.ID,
+		Status:   uint(common_models.OrderCanceled),
+		StatusAt: time.Now().UTC(),
+		Source:   actorSource(c, customerActor),
+	}
+
+	err := order.orderCommandHandler.UpdateStatusOrderCommandHandler(ctx, command)
+	if err != nil {
+		commandError(c, err)
+		return
+	}
+
+	httputil.NewResponseSuccess(c, http.StatusOK, "order canceled")
+}
+
+func (order *OrderController) Create(c *gin.Context) {
+	ctx, span := trace.NewSpan(c.Request.Context(), "OrderController.Create")
+	defer span.End()
+
+	customerID, ok := order.customerID(c)
+	if !ok {
+		return
+	}
+
+	command := &commands.CreateOrderCommand{}
+	if err := c.ShouldBindJSON(command); err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, "invalid order")
+		return
+	}
+
+	if command.CustomerID.IsZero() {
+		command.CustomerID = customerID
+	}
+
+	if command.CustomerID != customerID {
 		httputil.NewResponseError(c, http.StatusForbidden, "invalid customer")
 		return
 	}
 
-	isID := helpers.IsValidID(ID.(string))
-	if !isID {
-		httputil.NewResponseError(c, http.StatusBadRequest, "invalid customerId")
+	if command.ID.IsZero() {
+		command.ID = primitive.NewObjectID()
+	}
+
+	command.Source = actorSource(c, customerActor)
+
+	err := order.orderCommandHandler.CreateOrderCommandHandler(ctx, command)
+	if err != nil {
+		commandError(c, err)
+		return
+	}
+
+	orderModel, err := order.orderRepository.FindByID(ctx, command.ID)
+	if err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, "order get error")
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("%s%s/%s", location.Get(c).String(), c.Request.URL.Path, orderModel.ID.Hex()))
+	c.JSON(http.StatusCreated, orderModel)
+}
+
+func (order *OrderController) CancelItems(c *gin.Context) {
+	ctx, span := trace.NewSpan(c.Request.Context(), "OrderController.CancelItems")
+	defer span.End()
+
+	customerID, ok := order.customerID(c)
+	if !ok {
+		return
+	}
+
+	orderModel, ok := order.customerOrder(c, customerID)
+	if !ok {
+		return
+	}
+
+	if !statemachine.CanCancelItems(common_models.Status(orderModel.Status)) {
+		httputil.NewResponseError(c, http.StatusConflict,
+			fmt.Sprintf("order items cannot be canceled: %s", common_models.Status(orderModel.Status)))
 		return
 	}
 
-	customerID := helpers.StringToID(ID.(string))
+	command := &commands.CancelItemsOrderCommand{}
+	if err := c.ShouldBindJSON(command); err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	command.ID = orderModel.ID
+	command.Source = actorSource(c, customerActor)
+
+	err := order.orderCommandHandler.CancelItemsOrderCommandHandler(ctx, command)
+	if err != nil {
+		commandError(c, err)
+		return
+	}
+
+	result, err := order.orderRepository.FindByID(ctx, orderModel.ID)
+	if err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, "order get error")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (order *OrderController) RequestReturn(c *gin.Context) {
+	ctx, span := trace.NewSpan(c.Request.Context(), "OrderController.RequestReturn")
+	defer span.End()
+
+	customerID, ok := order.customerID(c)
+	if !ok {
+		return
+	}
+
+	orderModel, ok := order.customerOrder(c, customerID)
+	if !ok {
+		return
+	}
+
+	command := &commands.RequestReturnCommand{}
+	if err := c.ShouldBindJSON(command); err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	command.ID = orderModel.ID
+	command.ReturnID = primitive.NewObjectID()
+	command.Source = actorSource(c, customerActor)
+
+	err := order.orderCommandHandler.RequestReturnCommandHandler(ctx, command)
+	if err != nil {
+		commandError(c, err)
+		return
+	}
+
+	result, err := order.orderRepository.FindByID(ctx, orderModel.ID)
+	if err != nil {
+		httputil.NewResponseError(c, http.StatusBadRequest, "order get error")
+		return
+	}
+
+	for _, returnRequest := range result.Returns {
+		if returnRequest.ID == command.ReturnID {
+			c.Header("Location", fmt.Sprintf("%s%s/%s", location.Get(c).String(), c.Request.URL.Path, returnRequest.ID.Hex()))
+			c.JSON(http.StatusCreated, returnRequest)
+			return
+		}
+	}
+
+	httputil.NewResponseError(c, http.StatusBadRequest, "return get error")
+}
+
+func (order *OrderController) GetReturns(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "OrderController.GetReturns")
+	defer span.End()
+
+	customerID, ok := order.customerID(c)
+	if !ok {
+		return
+	}
+
+	orderModel, ok := order.customerOrder(c, customerID)
+	if !ok {
+		return
+	}
+
+	returns := orderModel.Returns
+	if returns == nil {
+		returns = []*models.ReturnRequest{}
+	}
+
+	c.JSON(http.StatusOK, returns)
+}
+
+func (order *OrderController) GetShipments(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "OrderController.GetShipments")
+	defer span.End()
+
+	customerID, ok := order.customerID(c)
+	if !ok {
+		return
+	}
+
+	orderModel, ok := order.customerOrder(c, customerID)
+	if !ok {
+		return
+	}
+
+	shipments := orderModel.Shipments
+	if shipments == nil {
+		shipments = []*models.Shipment{}
+	}
+
+	c.JSON(http.StatusOK, shipments)
+}
+
+func (order *OrderController) GetHistory(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "OrderController.GetHistory")
+	defer span.End()
+
+	customerID, ok := order.customerID(c)
+	if !ok {
+		return
+	}
+
+	orderModel, ok := order.customerOrder(c, customerID)
+	if !ok {
+		return
+	}
+
+	history := orderModel.History
+	if history == nil {
+		history = []*models.StatusHistory{}
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (order *OrderController) GetLast(c *gin.Context) {
+	_, span := trace.NewSpan(c.Request.Context(), "OrderController.GetLast")
+	defer span.End()
+
+	customerID, ok := order.customerID(c)
+	if !ok {
+		return
+	}
 
 	orderModel, err := order.orderRepository.FindByCustomerID(c.Request.Context(), customerID)
 	if orderModel == nil || err != nil {
@@ -76,3 +382,40 @@ func (order *OrderController) GetById(c *gin.Context) {
 
 	c.JSON(http.StatusOK, orderModel)
 }
+
+func (order *OrderController) customerOrder(c *gin.Context, customerID primitive.ObjectID) (*models.Order, bool) {
+	ID := c.Param("id")
+	if !helpers.IsValidID(ID) {
+		httputil.NewResponseError(c, http.StatusBadRequest, "invalid orderId")
+		return nil, false
+	}
+
+	orderModel, err := order.orderRepository.FindByID(c.Request.Context(), helpers.StringToID(ID))
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		httputil.NewResponseError(c, http.StatusBadRequest, "order get error")
+		return nil, false
+	}
+
+	if orderModel == nil || orderModel.CustomerID != customerID {
+		httputil.NewResponseError(c, http.StatusNotFound, "order not found")
+		return nil, false
+	}
+
+	return orderModel, true
+}
+
+func (order *OrderController) customerID(c *gin.Context) (primitive.ObjectID, bool) {
+	ID, customerIDOk := c.Get("user")
+	if !customerIDOk {
+		httputil.NewResponseError(c, http.StatusForbidden, "invalid customer")
+		return primitive.NilObjectID, false
+	}
+
+	isID := helpers.IsValidID(ID.(string))
+	if !isID {
+		httputil.NewResponseError(c, http.StatusBadRequest, "invalid customerId")
+		return primitive.NilObjectID, false
+	}
+
+	return helpers.StringToID(ID.(string)), true
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"order/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func parseOrderQueryOptions(c *gin.Context) (*models.OrderQueryOptions, error) {
+	queryOptions := &models.OrderQueryOptions{
+		Cursor: c.Query("cursor"),
+	}
+
+	if limit := c.Query("limit"); len(limit) > 0 {
+		value, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid limit: %s", limit)
+		}
+		queryOptions.Limit = value
+	}
+
+	for _, statuses := range c.QueryArray("status") {
+		for _, status := range strings.Split(statuses, ",") {
+			value, err := strconv.ParseUint(strings.TrimSpace(status), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid status: %s", status)
+			}
+			queryOptions.Statuses = append(queryOptions.Statuses, uint(value))
+		}
+	}
+
+	var err error
+	queryOptions.CreatedFrom, err = parseQueryTime(c.Query("from"), false)
+	if err != nil {
+		return nil, err
+	}
+
+	queryOptions.CreatedTo, err = parseQueryTime(c.Query("to"), true)
+	if err != nil {
+		return nil, err
+	}
+
+	if sort := c.Query("sort"); len(sort) > 0 {
+		queryOptions.SortAsc = !strings.HasPrefix(sort, "-")
+		queryOptions.SortField = strings.TrimPrefix(sort, "-")
+	}
+
+	return queryOptions, nil
+}
+
+func parseQueryTime(value string, endOfDay bool) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date.UTC(), nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date: %s", value)
+	}
+
+	if endOfDay {
+		date = date.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return date, nil
+}
@@ -7,11 +7,18 @@ import (
 )
 
 type AddOrder struct {
-	ID         primitive.ObjectID `json:"id"`
-	CustomerID primitive.ObjectID `json:"customerId"`
-	Products   []*models.Product  `json:"products"`
-	Stores     []*models.Store    `json:"stores"`
-	Sum        float32            `json:"sum"`
-	Discount   float32            `json:"discount"`
-	Status     uint               `json:"status"`
+	ID              primitive.ObjectID `json:"id"`
+	CustomerID      primitive.ObjectID `json:"customerId"`
+	Products        []*models.Product  `json:"products"`
+	Stores          []*models.Store    `json:"stores"`
+	Sum             models.Money       `json:"sum"`
+	Discount        models.Money       `json:"discount"`
+	Currency        string             `json:"currency"`
+	Region          string             `json:"region"`
+	Coupons         []string           `json:"coupons"`
+	ShippingAddress *models.Address    `json:"shippingAddress"`
+	BillingAddress  *models.Address    `json:"billingAddress"`
+	DeliveryMethod  string             `json:"deliveryMethod"`
+	ShippingCost    models.Money       `json:"shippingCost"`
+	Status          uint               `json:"status"`
 }
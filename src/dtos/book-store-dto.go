@@ -7,6 +7,8 @@ import (
 )
 
 type BookStore struct {
-	OrderID  primitive.ObjectID `json:"orderId"`
-	Products []*models.Product  `json:"products"`
+	OrderID         primitive.ObjectID `json:"orderId"`
+	Products        []*models.Product  `json:"products"`
+	ShippingAddress *models.Address    `json:"shippingAddress"`
+	DeliveryMethod  string             `json:"deliveryMethod"`
 }
@@ -0,0 +1,16 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CancelItemsOrder struct {
+	ID    primitive.ObjectID `json:"id"`
+	Items []*CancelItem      `json:"items"`
+}
+
+type CancelItem struct {
+	ProductID uuid.UUID `json:"productId"`
+	Quantity  uint      `json:"quantity"`
+}
@@ -0,0 +1,13 @@
+package dtos
+
+import "encoding/json"
+
+type ResubmitDeadLetter struct {
+	Data json.RawMessage `json:"data"`
+}
+
+type ReplayDeadLetters struct {
+	From    uint64 `json:"from"`
+	To      uint64 `json:"to"`
+	Subject string `json:"subject"`
+}
@@ -0,0 +1,12 @@
+package dtos
+
+import (
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RequestReturn struct {
+	ID    primitive.ObjectID   `json:"id"`
+	Items []*models.ReturnItem `json:"items"`
+}
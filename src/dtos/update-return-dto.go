@@ -0,0 +1,15 @@
+package dtos
+
+import (
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UpdateReturn struct {
+	ID       primitive.ObjectID `json:"id"`
+	ReturnID primitive.ObjectID `json:"returnId"`
+	Status   string             `json:"status"`
+	Note     string             `json:"note"`
+	Refund   *models.Money      `json:"refund"`
+}
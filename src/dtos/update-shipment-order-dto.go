@@ -0,0 +1,15 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UpdateShipmentOrder struct {
+	ID             primitive.ObjectID `json:"orderId"`
+	ShipmentID     primitive.ObjectID `json:"shipmentId"`
+	StoreID        uuid.UUID          `json:"storeId"`
+	Status         string             `json:"status"`
+	Carrier        string             `json:"carrier"`
+	TrackingNumber string             `json:"trackingNumber"`
+}
@@ -0,0 +1,20 @@
+package models
+
+const (
+	DeliveryStandard = "standard"
+	DeliveryExpress  = "express"
+	DeliveryPickup   = "pickup"
+)
+
+type Address struct {
+	Name       string `bson:"name" json:"name"`
+	Street     string `bson:"street" json:"street"`
+	Number     string `bson:"number" json:"number"`
+	Complement string `bson:"complement" json:"complement,omitempty"`
+	District   string `bson:"district" json:"district,omitempty"`
+	City       string `bson:"city" json:"city"`
+	State      string `bson:"state" json:"state"`
+	PostalCode string `bson:"postal_code" json:"postalCode"`
+	Country    string `bson:"country" json:"country"`
+	Phone      string `bson:"phone" json:"phone,omitempty"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CanceledItem struct {
+	ProductID  uuid.UUID `bson:"product_id" json:"productId"`
+	Quantity   uint      `bson:"quantity" json:"quantity"`
+	Amount     Money     `bson:"amount" json:"amount"`
+	CanceledAt time.Time `bson:"canceled_at" json:"canceledAt"`
+	Source     string    `bson:"source" json:"source,omitempty"`
+}
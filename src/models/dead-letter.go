@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// DeadLetter is a command parked in the dead-letter stream after it failed.
+type DeadLetter struct {
+	Sequence         uint64    `json:"sequence"`
+	Subject          string    `json:"subject"`
+	Stream           string    `json:"stream"`
+	Consumer         string    `json:"consumer"`
+	OriginalSequence uint64    `json:"originalSequence"`
+	Deliveries       uint64    `json:"deliveries"`
+	Error            string    `json:"error"`
+	Permanent        bool      `json:"permanent"`
+	FailedAt         time.Time `json:"failedAt"`
+	Data             string    `json:"data"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type ExchangeRate struct {
+	Base   string    `bson:"base" json:"base"`
+	Quote  string    `bson:"quote" json:"quote"`
+	Rate   string    `bson:"rate" json:"rate"`
+	Source string    `bson:"source" json:"source"`
+	At     time.Time `bson:"at" json:"at"`
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+var DefaultCurrency = "BRL"
+
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+var currencyExponents = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"IQD": 3,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"LYD": 3,
+	"OMR": 3,
+	"PYG": 0,
+	"TND": 3,
+	"VND": 0,
+}
+
+// Money is an amount in the minor units of an ISO 4217 currency. Legacy
+// documents and payloads that still carry plain decimal numbers are read as
+// major units of DefaultCurrency.
+type Money struct {
+	Amount   int64  `bson:"amount" json:"amount"`
+	Currency string `bson:"currency" json:"currency"`
+}
+
+type money Money
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: normalizeCurrency(currency)}
+}
+
+func MoneyFromMajor(value float64, currency string) Money {
+	currency = normalizeCurrency(currency)
+	factor := math.Pow10(CurrencyExponent(currency))
+
+	return Money{Amount: int64(math.Round(value * factor)), Currency: currency}
+}
+
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[strings.ToUpper(currency)]; ok {
+		return exponent
+	}
+
+	return 2
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if other.Amount == 0 {
+		return m, nil
+	}
+
+	if m.Amount == 0 && len(m.Currency) == 0 {
+		return other, nil
+	}
+
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	return m.Add(Money{Amount: -other.Amount, Currency: other.Currency})
+}
+
+func (m Money) Multiply(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// MultiplyRate applies a fractional rate such as a tax rate, rounding half
+// away from zero to the minor unit.
+func (m Money) MultiplyRate(rate *big.Rat) Money {
+	value := new(big.Rat).SetInt64(m.Amount)
+	value.Mul(value, rate)
+
+	numerator := new(big.Int).Set(value.Num())
+	half := new(big.Int).Quo(value.Denom(), big.NewInt(2))
+	if numerator.Sign() < 0 {
+		numerator.Sub(numerator, half)
+	} else {
+		numerator.Add(numerator, half)
+	}
+
+	return Money{Amount: numerator.Quo(numerator, value.Denom()).Int64(), Currency: m.Currency}
+}
+
+// Major formats the amount as an exact decimal string in major units.
+func (m Money) Major() string {
+	exponent := CurrencyExponent(m.Currency)
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	if exponent == 0 {
+		return fmt.Sprintf("%s%d", sign, amount)
+	}
+
+	factor := int64(math.Pow10(exponent))
+
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/factor, exponent, amount%factor)
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Major(), m.Currency)
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var value float64
+	if err := json.Unmarshal(data, &value); err == nil {
+		*m = MoneyFromMajor(value, DefaultCurrency)
+		return nil
+	}
+
+	object := money{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+
+	*m = NewMoney(object.Amount, object.Currency)
+
+	return nil
+}
+
+func (m Money) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(money(m))
+}
+
+func (m *Money) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	raw := bson.RawValue{Type: t, Value: data}
+
+	switch t {
+	case bsontype.Double:
+		*m = MoneyFromMajor(raw.Double(), DefaultCurrency)
+	case bsontype.Int32:
+		*m = MoneyFromMajor(float64(raw.Int32()), DefaultCurrency)
+	case bsontype.Int64:
+		*m = MoneyFromMajor(float64(raw.Int64()), DefaultCurrency)
+	case bsontype.EmbeddedDocument:
+		object := money{}
+		if err := raw.Unmarshal(&object); err != nil {
+			return err
+		}
+		*m = NewMoney(object.Amount, object.Currency)
+	case bsontype.Null, bsontype.Undefined:
+		*m = Money{}
+	default:
+		return fmt.Errorf("cannot decode %s into Money", t)
+	}
+
+	return nil
+}
+
+func normalizeCurrency(currency string) string {
+	if len(currency) == 0 {
+		return DefaultCurrency
+	}
+
+	return strings.ToUpper(currency)
+}
@@ -0,0 +1,12 @@
+package models
+
+import "github.com/google/uuid"
+
+type OrderLine struct {
+	ProductID uuid.UUID `bson:"product_id" json:"productId"`
+	Quantity  uint      `bson:"quantity" json:"quantity"`
+	Category  string    `bson:"category" json:"category,omitempty"`
+	Currency  string    `bson:"currency" json:"currency"`
+	UnitPrice Money     `bson:"unit_price" json:"unitPrice"`
+	Total     Money     `bson:"total" json:"total"`
+}
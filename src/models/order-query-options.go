@@ -0,0 +1,37 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	OrderQueryDefaultLimit int64 = 20
+	OrderQueryMaxLimit     int64 = 100
+)
+
+var (
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidSortField = errors.New("invalid sort field")
+)
+
+type OrderQueryOptions struct {
+	CustomerID  primitive.ObjectID
+	ProductID   uuid.UUID
+	Statuses    []uint
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	SortField   string
+	SortAsc     bool
+	Limit       int64
+	Cursor      string
+}
+
+type OrderPage struct {
+	Orders     []*Order `json:"orders"`
+	NextCursor string   `json:"nextCursor,omitempty"`
+	Total      int64    `json:"total"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	SagaRunning     = "running"
+	SagaCompleted   = "completed"
+	SagaCompensated = "compensated"
+	SagaStalled     = "stalled"
+)
+
+const (
+	SagaStepPending     = "pending"
+	SagaStepStarted     = "started"
+	SagaStepCompleted   = "completed"
+	SagaStepFailed      = "failed"
+	SagaStepCompensated = "compensated"
+)
+
+// OrderSaga records how far an order got through checkout. Each step keeps
+// the command that starts it so a stalled step can be sent again, and the
+// subject of the command that undoes it.
+type OrderSaga struct {
+	OrderID     primitive.ObjectID `bson:"_id" json:"orderId"`
+	Status      string             `bson:"status" json:"status"`
+	CurrentStep string             `bson:"current_step" json:"currentStep"`
+	Steps       []*SagaStep        `bson:"steps" json:"steps"`
+	Reason      string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updatedAt"`
+	Version     uint               `bson:"version" json:"version"`
+}
+
+type SagaStep struct {
+	Name                string    `bson:"name" json:"name"`
+	Status              string    `bson:"status" json:"status"`
+	ActionSubject       string    `bson:"action_subject" json:"actionSubject"`
+	ActionData          string    `bson:"action_data,omitempty" json:"actionData,omitempty"`
+	Redacted            bool      `bson:"redacted" json:"redacted"`
+	CompensationSubject string    `bson:"compensation_subject" json:"compensationSubject"`
+	Attempts            uint      `bson:"attempts" json:"attempts"`
+	Progress            string    `bson:"progress,omitempty" json:"progress,omitempty"`
+	Error               string    `bson:"error,omitempty" json:"error,omitempty"`
+	StartedAt           time.Time `bson:"started_at,omitempty" json:"startedAt,omitempty"`
+	LastAttemptAt       time.Time `bson:"last_attempt_at,omitempty" json:"lastAttemptAt,omitempty"`
+	CompletedAt         time.Time `bson:"completed_at,omitempty" json:"completedAt,omitempty"`
+	CompensatedAt       time.Time `bson:"compensated_at,omitempty" json:"compensatedAt,omitempty"`
+}
+
+func (s *OrderSaga) Step(name string) *SagaStep {
+	for _, step := range s.Steps {
+		if step.Name == name {
+			return step
+		}
+	}
+
+	return nil
+}
@@ -7,16 +7,32 @@ import (
 )
 
 type Order struct {
-	ID         primitive.ObjectID `bson:"_id" json:"id"`
-	CustomerID primitive.ObjectID `bson:"customer_id" json:"customerId"`
-	Products   []*Product         `bson:"products" json:"products"`
-	Stores     []*Store           `bson:"stores" json:"stores"`
-	Sum        float32            `bson:"sum" json:"sum"`
-	Discount   float32            `bson:"discount" json:"discount"`
-	Status     uint               `bson:"status" json:"status"`
-	StatusAt   time.Time          `bson:"status_at" json:"status_at"`
-	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at,omitempty"`
-	Version    uint               `bson:"version" json:"version"`
-	Deleted    bool               `bson:"deleted" json:"deleted,omitempty"`
+	ID                primitive.ObjectID  `bson:"_id" json:"id"`
+	CustomerID        primitive.ObjectID  `bson:"customer_id" json:"customerId"`
+	Products          []*Product          `bson:"products" json:"products"`
+	Stores            []*Store            `bson:"stores" json:"stores"`
+	Lines             []*OrderLine        `bson:"lines" json:"lines"`
+	Sum               Money               `bson:"sum" json:"sum"`
+	Discount          Money               `bson:"discount" json:"discount"`
+	CanceledItems     []*CanceledItem     `bson:"canceled_items" json:"canceledItems,omitempty"`
+	Shipments         []*Shipment         `bson:"shipments" json:"shipments,omitempty"`
+	FulfillmentStatus string              `bson:"fulfillment_status" json:"fulfillmentStatus,omitempty"`
+	Returns           []*ReturnRequest    `bson:"returns" json:"returns,omitempty"`
+	Promotions        []*AppliedPromotion `bson:"promotions" json:"promotions,omitempty"`
+	Region            string              `bson:"region" json:"region,omitempty"`
+	TaxLines          []*TaxLine          `bson:"tax_lines" json:"taxLines,omitempty"`
+	Tax               Money               `bson:"tax" json:"tax"`
+	Currency          string              `bson:"currency" json:"currency"`
+	ExchangeRate      *ExchangeRate       `bson:"exchange_rate" json:"exchangeRate,omitempty"`
+	ShippingAddress   *Address            `bson:"shipping_address" json:"shippingAddress,omitempty"`
+	BillingAddress    *Address            `bson:"billing_address" json:"billingAddress,omitempty"`
+	DeliveryMethod    string              `bson:"delivery_method" json:"deliveryMethod,omitempty"`
+	ShippingCost      Money               `bson:"shipping_cost" json:"shippingCost"`
+	Status            uint                `bson:"status" json:"status"`
+	StatusAt          time.Time           `bson:"status_at" json:"status_at"`
+	History           []*StatusHistory    `bson:"status_history" json:"statusHistory,omitempty"`
+	CreatedAt         time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `bson:"updated_at" json:"updated_at,omitempty"`
+	Version           uint                `bson:"version" json:"version"`
+	Deleted           bool                `bson:"deleted" json:"deleted,omitempty"`
 }
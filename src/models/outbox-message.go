@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OutboxMessage struct {
+	ID            primitive.ObjectID `bson:"_id" json:"id"`
+	OrderID       primitive.ObjectID `bson:"order_id" json:"orderId"`
+	Subject       string             `bson:"subject" json:"subject"`
+	MsgID         string             `bson:"msg_id,omitempty" json:"msgId,omitempty"`
+	Data          string             `bson:"data" json:"data"`
+	Published     bool               `bson:"published" json:"published"`
+	PublishedAt   time.Time          `bson:"published_at,omitempty" json:"published_at,omitempty"`
+	Attempts      uint               `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at" json:"next_attempt_at"`
+	LastError     string             `bson:"last_error,omitempty" json:"lastError,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+}
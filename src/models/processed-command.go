@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	CommandProcessing = "processing"
+	CommandSucceeded  = "succeeded"
+	CommandFailed     = "failed"
+)
+
+type ProcessedCommand struct {
+	Key         string    `bson:"_id" json:"key"`
+	Subject     string    `bson:"subject" json:"subject"`
+	Status      string    `bson:"status" json:"status"`
+	Error       string    `bson:"error,omitempty" json:"error,omitempty"`
+	LockedUntil time.Time `bson:"locked_until" json:"locked_until"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+	CompletedAt time.Time `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	ExpiresAt   time.Time `bson:"expires_at" json:"expires_at"`
+}
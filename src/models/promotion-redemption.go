@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PromotionRedemption struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	Code       string             `bson:"code" json:"code"`
+	CustomerID primitive.ObjectID `bson:"customer_id" json:"customerId"`
+	OrderID    primitive.ObjectID `bson:"order_id" json:"orderId"`
+	RedeemedAt time.Time          `bson:"redeemed_at" json:"redeemedAt"`
+	Released   bool               `bson:"released" json:"released"`
+	ReleasedAt time.Time          `bson:"released_at,omitempty" json:"releasedAt,omitempty"`
+}
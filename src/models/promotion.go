@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PromotionPercentage = "percentage"
+	PromotionFixed      = "fixed"
+	PromotionBuyXGetY   = "buy_x_get_y"
+)
+
+// Promotion is a discount rule. Promotions without a code apply
+// automatically to every eligible order; the others need their code on the
+// order. ProductIDs, when set, restricts the rule to those products.
+type Promotion struct {
+	Code             string      `json:"code"`
+	Name             string      `json:"name"`
+	Type             string      `json:"type"`
+	Percentage       string      `json:"percentage,omitempty"`
+	Amount           Money       `json:"amount,omitempty"`
+	BuyQuantity      uint        `json:"buyQuantity,omitempty"`
+	GetQuantity      uint        `json:"getQuantity,omitempty"`
+	ProductIDs       []uuid.UUID `json:"productIds,omitempty"`
+	MinOrder         Money       `json:"minOrder,omitempty"`
+	PerCustomerLimit uint        `json:"perCustomerLimit,omitempty"`
+	Stackable        bool        `json:"stackable"`
+	StartsAt         *time.Time  `json:"startsAt,omitempty"`
+	EndsAt           *time.Time  `json:"endsAt,omitempty"`
+}
+
+type AppliedPromotion struct {
+	Code   string `bson:"code" json:"code,omitempty"`
+	Name   string `bson:"name" json:"name"`
+	Type   string `bson:"type" json:"type"`
+	Amount Money  `bson:"amount" json:"amount"`
+}
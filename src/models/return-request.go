@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ReturnRequested = "requested"
+	ReturnApproved  = "approved"
+	ReturnRejected  = "rejected"
+	ReturnReceived  = "received"
+	ReturnRefunded  = "refunded"
+)
+
+const (
+	ReturnReasonDamaged        = "damaged"
+	ReturnReasonWrongItem      = "wrong_item"
+	ReturnReasonNotAsDescribed = "not_as_described"
+	ReturnReasonNoLongerNeeded = "no_longer_needed"
+	ReturnReasonOther          = "other"
+)
+
+// ReturnRequest is a return merchandise authorization (RMA) for some of the
+// items of an order.
+type ReturnRequest struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	Items       []*ReturnItem      `bson:"items" json:"items"`
+	Status      string             `bson:"status" json:"status"`
+	Refund      Money              `bson:"refund" json:"refund"`
+	Note        string             `bson:"note" json:"note,omitempty"`
+	RequestedBy string             `bson:"requested_by" json:"requestedBy,omitempty"`
+	UpdatedBy   string             `bson:"updated_by" json:"updatedBy,omitempty"`
+	RequestedAt time.Time          `bson:"requested_at" json:"requestedAt"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updatedAt"`
+}
+
+type ReturnItem struct {
+	ProductID uuid.UUID `bson:"product_id" json:"productId"`
+	Quantity  uint      `bson:"quantity" json:"quantity"`
+	Reason    string    `bson:"reason" json:"reason"`
+	Comment   string    `bson:"comment" json:"comment,omitempty"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ShipmentPending   = "pending"
+	ShipmentPacked    = "packed"
+	ShipmentShipped   = "shipped"
+	ShipmentDelivered = "delivered"
+)
+
+const (
+	FulfillmentUnfulfilled        = "unfulfilled"
+	FulfillmentPartiallyShipped   = "partially_shipped"
+	FulfillmentShipped            = "shipped"
+	FulfillmentPartiallyDelivered = "partially_delivered"
+	FulfillmentDelivered          = "delivered"
+)
+
+// Shipment groups the products of an order booked by the same store, which
+// ships them on its own.
+type Shipment struct {
+	ID             primitive.ObjectID `bson:"_id" json:"id"`
+	StoreID        uuid.UUID          `bson:"store_id" json:"storeId"`
+	ProductIDs     []uuid.UUID        `bson:"product_ids" json:"productIds"`
+	Status         string             `bson:"status" json:"status"`
+	Carrier        string             `bson:"carrier" json:"carrier,omitempty"`
+	TrackingNumber string             `bson:"tracking_number" json:"trackingNumber,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at" json:"createdAt"`
+	PackedAt       time.Time          `bson:"packed_at,omitempty" json:"packedAt,omitempty"`
+	ShippedAt      time.Time          `bson:"shipped_at,omitempty" json:"shippedAt,omitempty"`
+	DeliveredAt    time.Time          `bson:"delivered_at,omitempty" json:"deliveredAt,omitempty"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updatedAt"`
+}
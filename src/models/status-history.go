@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type StatusHistory struct {
+	PreviousStatus uint      `bson:"previous_status" json:"previousStatus"`
+	Status         uint      `bson:"status" json:"status"`
+	StatusAt       time.Time `bson:"status_at" json:"status_at"`
+	Source         string    `bson:"source" json:"source"`
+	Version        uint      `bson:"version" json:"version"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type StoredEvent struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	OrderID    primitive.ObjectID `bson:"order_id" json:"orderId"`
+	Version    uint               `bson:"version" json:"version"`
+	Type       string             `bson:"type" json:"type"`
+	Data       string             `bson:"data" json:"data"`
+	OccurredAt time.Time          `bson:"occurred_at" json:"occurred_at"`
+}
+
+type OrderSnapshot struct {
+	OrderID    primitive.ObjectID `bson:"order_id" json:"orderId"`
+	Version    uint               `bson:"version" json:"version"`
+	State      string             `bson:"state" json:"state"`
+	OccurredAt time.Time          `bson:"occurred_at" json:"occurred_at"`
+	TakenAt    time.Time          `bson:"taken_at" json:"taken_at"`
+}
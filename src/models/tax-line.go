@@ -0,0 +1,13 @@
+package models
+
+import "github.com/google/uuid"
+
+type TaxLine struct {
+	ProductID uuid.UUID `bson:"product_id" json:"productId"`
+	Region    string    `bson:"region" json:"region"`
+	Category  string    `bson:"category" json:"category"`
+	Name      string    `bson:"name" json:"name"`
+	Rate      string    `bson:"rate" json:"rate"`
+	Taxable   Money     `bson:"taxable" json:"taxable"`
+	Amount    Money     `bson:"amount" json:"amount"`
+}
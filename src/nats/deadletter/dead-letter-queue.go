@@ -0,0 +1,204 @@
+package deadletter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"order/src/models"
+	"order/src/nats/retry"
+	"order/src/nats/subjects"
+
+	common_nats "github.com/JohnSalazar/microservices-go-common/nats"
+	"github.com/nats-io/nats.go"
+)
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+type NotReplayableError struct {
+	Subject string
+}
+
+func (e *NotReplayableError) Error() string {
+	return fmt.Sprintf("subject %q cannot be replayed", e.Subject)
+}
+
+// replayable are the subjects this service listens to; a dead letter can only
+// be sent back to one of them.
+var replayable = map[string]bool{
+	string(common_nats.OrderCreate):      true,
+	string(common_nats.OrderStatus):      true,
+	string(common_nats.StoreBooked):      true,
+	string(subjects.OrderShipmentUpdate): true,
+	string(subjects.StoreBookFailed):     true,
+}
+
+// DeadLetterQueue reads the dead-letter stream filled by retry.RetryPolicy
+// and sends parked commands back to the subject they failed on.
+type DeadLetterQueue struct {
+	js     nats.JetStreamContext
+	stream string
+}
+
+func NewDeadLetterQueue(
+	js nats.JetStreamContext,
+	stream string,
+) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		js:     js,
+		stream: stream,
+	}
+}
+
+// List returns up to limit dead letters starting at sequence from.
+func (q *DeadLetterQueue) List(from uint64, limit int) ([]*models.DeadLetter, error) {
+	info, err := q.js.StreamInfo(q.stream)
+	if err != nil {
+		return nil, err
+	}
+
+	if from < info.State.FirstSeq {
+		from = info.State.FirstSeq
+	}
+
+	deadLetters := []*models.DeadLetter{}
+	for seq := from; seq <= info.State.LastSeq && len(deadLetters) < limit; seq++ {
+		deadLetter, err := q.Get(seq)
+		if errors.Is(err, ErrDeadLetterNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	return deadLetters, nil
+}
+
+func (q *DeadLetterQueue) Get(seq uint64) (*models.DeadLetter, error) {
+	msg, err := q.js.GetMsg(q.stream, seq)
+	if errors.Is(err, nats.ErrMsgNotFound) {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapDeadLetter(msg), nil
+}
+
+// Resubmit sends the dead letter back to its subject, with data replacing the
+// payload when given, and removes it from the queue.
+func (q *DeadLetterQueue) Resubmit(seq uint64, data []byte) error {
+	deadLetter, err := q.Get(seq)
+	if err != nil {
+		return err
+	}
+
+	if len(data) == 0 {
+		data = []byte(deadLetter.Data)
+	}
+
+	return q.resubmit(deadLetter, data)
+}
+
+// Replay resubmits the dead letters between from and to, inclusive. A
+// non-empty subject only replays the ones that failed on it.
+func (q *DeadLetterQueue) Replay(from uint64, to uint64, subject string) (int, error) {
+	if len(subject) > 0 && !replayable[subject] {
+		return 0, &NotReplayableError{Subject: subject}
+	}
+
+	info, err := q.js.StreamInfo(q.stream)
+	if err != nil {
+		return 0, err
+	}
+
+	if from < info.State.FirstSeq {
+		from = info.State.FirstSeq
+	}
+
+	if to > info.State.LastSeq {
+		to = info.State.LastSeq
+	}
+
+	replayed := 0
+	for seq := from; seq <= to; seq++ {
+		deadLetter, err := q.Get(seq)
+		if errors.Is(err, ErrDeadLetterNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return replayed, err
+		}
+
+		if len(subject) > 0 && deadLetter.Subject != subject {
+			continue
+		}
+
+		err = q.resubmit(deadLetter, []byte(deadLetter.Data))
+		if err != nil {
+			return replayed, err
+		}
+
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+func (q *DeadLetterQueue) Discard(seq uint64) error {
+	err := q.js.DeleteMsg(q.stream, seq)
+	if errors.Is(err, nats.ErrMsgNotFound) {
+		return ErrDeadLetterNotFound
+	}
+
+	return err
+}
+
+func (q *DeadLetterQueue) resubmit(deadLetter *models.DeadLetter, data []byte) error {
+	if !replayable[deadLetter.Subject] {
+		return &NotReplayableError{Subject: deadLetter.Subject}
+	}
+
+	// The ID lets the target stream drop the copy if the discard below fails
+	// and the dead letter is resubmitted again.
+	msg := nats.NewMsg(deadLetter.Subject)
+	msg.Data = data
+	msg.Header.Set(nats.MsgIdHdr, fmt.Sprintf("dlq:%s:%d", q.stream, deadLetter.Sequence))
+
+	_, err := q.js.PublishMsg(msg)
+	if err != nil {
+		return err
+	}
+
+	return q.Discard(deadLetter.Sequence)
+}
+
+func mapDeadLetter(msg *nats.RawStreamMsg) *models.DeadLetter {
+	deadLetter := &models.DeadLetter{
+		Sequence: msg.Sequence,
+		Subject:  msg.Header.Get(retry.HeaderSubject),
+		Stream:   msg.Header.Get(retry.HeaderStream),
+		Consumer: msg.Header.Get(retry.HeaderConsumer),
+		Error:    msg.Header.Get(retry.HeaderError),
+		Data:     string(msg.Data),
+		FailedAt: msg.Time,
+	}
+
+	deadLetter.OriginalSequence, _ = strconv.ParseUint(msg.Header.Get(retry.HeaderSequence), 10, 64)
+	deadLetter.Deliveries, _ = strconv.ParseUint(msg.Header.Get(retry.HeaderDeliveries), 10, 64)
+	deadLetter.Permanent, _ = strconv.ParseBool(msg.Header.Get(retry.HeaderPermanent))
+
+	if failedAt, err := time.Parse(time.RFC3339, msg.Header.Get(retry.HeaderFailedAt)); err == nil {
+		deadLetter.FailedAt = failedAt
+	}
+
+	return deadLetter
+}
@@ -0,0 +1,136 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"order/src/models"
+	"order/src/repositories/interfaces"
+	"order/src/settings"
+
+	"github.com/nats-io/nats.go"
+)
+
+var ErrCommandInProgress = errors.New("command is being processed by another delivery")
+
+const (
+	// heartbeatInterval keeps a running command's delivery below the
+	// listeners' 5s ack wait so the server does not redeliver it meanwhile.
+	heartbeatInterval = 2 * time.Second
+	// inProgressDelay is how long a delivery that found its command still
+	// running waits before it is delivered again.
+	inProgressDelay = 5 * time.Second
+)
+
+type IdempotencyGuard struct {
+	settings                   *settings.IdempotencySettings
+	processedCommandRepository interfaces.ProcessedCommandRepository
+}
+
+func NewIdempotencyGuard(
+	settings *settings.IdempotencySettings,
+	processedCommandRepository interfaces.ProcessedCommandRepository,
+) *IdempotencyGuard {
+	return &IdempotencyGuard{
+		settings:                   settings,
+		processedCommandRepository: processedCommandRepository,
+	}
+}
+
+func (g *IdempotencyGuard) Process(ctx context.Context, msg *nats.Msg, handler func(ctx context.Context) error) error {
+	key := commandKey(msg)
+	now := time.Now().UTC()
+	lockedUntil := now.Add(time.Duration(g.settings.ProcessingTimeoutSeconds) * time.Second)
+	expiresAt := now.Add(time.Duration(g.settings.WindowMinutes) * time.Minute)
+
+	started, err := g.processedCommandRepository.Begin(ctx, key, msg.Subject, lockedUntil, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	if !started {
+		processedCommand, err := g.processedCommandRepository.FindByKey(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		// Another delivery is still working on it and may yet fail, so this one
+		// must be retried rather than acknowledged.
+		if processedCommand != nil && processedCommand.Status == models.CommandProcessing {
+			return ErrCommandInProgress
+		}
+
+		log.Printf("%s command %s already processed\n", msg.Subject, key)
+		return nil
+	}
+
+	stop := heartbeat(msg)
+	commandErr := handler(ctx)
+	close(stop)
+
+	status, commandError := models.CommandSucceeded, ""
+	if commandErr != nil {
+		status, commandError = models.CommandFailed, commandErr.Error()
+	}
+
+	err = g.processedCommandRepository.Complete(ctx, key, status, commandError)
+	if err != nil {
+		log.Printf("%s command %s complete error: %v\n", msg.Subject, key, err)
+	}
+
+	return commandErr
+}
+
+// Ack settles a delivery once Process has returned. A delivery whose command
+// is still running elsewhere is redelivered later, in case that one fails.
+func Ack(msg *nats.Msg, err error) {
+	if errors.Is(err, ErrCommandInProgress) {
+		err = msg.NakWithDelay(inProgressDelay)
+	} else {
+		err = msg.Ack()
+	}
+
+	if err != nil {
+		log.Printf("stan msg.Ack error: %v\n", err)
+	}
+}
+
+func heartbeat(msg *nats.Msg) chan struct{} {
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				err := msg.InProgress()
+				if err != nil {
+					log.Printf("%s msg.InProgress error: %v\n", msg.Subject, err)
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return stop
+}
+
+func commandKey(msg *nats.Msg) string {
+	if msg.Header != nil {
+		if ID := msg.Header.Get(nats.MsgIdHdr); len(ID) > 0 {
+			return fmt.Sprintf("%s:%s", msg.Subject, ID)
+		}
+	}
+
+	hash := sha256.Sum256(msg.Data)
+
+	return fmt.Sprintf("%s:%s", msg.Subject, hex.EncodeToString(hash[:]))
+}
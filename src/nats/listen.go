@@ -2,9 +2,11 @@ package nats
 
 import (
 	"order/src/application/commands"
+	"order/src/nats/idempotency"
 	"order/src/nats/listeners"
+	"order/src/nats/retry"
+	"order/src/nats/subjects"
 
-	"github.com/JohnSalazar/microservices-go-common/config"
 	"github.com/nats-io/nats.go"
 
 	common_nats "github.com/JohnSalazar/microservices-go-common/nats"
@@ -18,26 +20,29 @@ type listen struct {
 const queueGroupName string = "orders-service"
 
 var (
-	subscribe          common_nats.Listener
-	commandErrorHelper *common_nats.CommandErrorHelper
+	subscribe common_nats.Listener
 
-	orderCreateCommand       *listeners.OrderCreateCommandListener
-	orderUpdateStatusCommand *listeners.OrderUpdateStatusCommandListener
-	orderUpdateStoreCommand  *listeners.OrderUpdateStoreCommandListener
+	orderCreateCommand         *listeners.OrderCreateCommandListener
+	orderUpdateStatusCommand   *listeners.OrderUpdateStatusCommandListener
+	orderUpdateStoreCommand    *listeners.OrderUpdateStoreCommandListener
+	orderUpdateShipmentCommand *listeners.OrderUpdateShipmentCommandListener
+	orderStoreBookingFailed    *listeners.OrderStoreBookingFailedCommandListener
 )
 
 func NewListen(
-	config *config.Config,
 	js nats.JetStreamContext,
 	orderCommandHandler *commands.OrderCommandHandler,
 	email common_service.EmailService,
+	idempotencyGuard *idempotency.IdempotencyGuard,
+	retryPolicy *retry.RetryPolicy,
 ) *listen {
 	subscribe = common_nats.NewListener(js)
-	commandErrorHelper = common_nats.NewCommandErrorHelper(config, email)
 
-	orderCreateCommand = listeners.NewOrderCreateCommandListener(orderCommandHandler, email, commandErrorHelper)
-	orderUpdateStatusCommand = listeners.NewOrderUpdateStatusCommandListener(orderCommandHandler, email, commandErrorHelper)
-	orderUpdateStoreCommand = listeners.NewOrderUpdateStoreCommandListener(orderCommandHandler, email, commandErrorHelper)
+	orderCreateCommand = listeners.NewOrderCreateCommandListener(orderCommandHandler, email, idempotencyGuard, retryPolicy)
+	orderUpdateStatusCommand = listeners.NewOrderUpdateStatusCommandListener(orderCommandHandler, email, idempotencyGuard, retryPolicy)
+	orderUpdateStoreCommand = listeners.NewOrderUpdateStoreCommandListener(orderCommandHandler, email, idempotencyGuard, retryPolicy)
+	orderUpdateShipmentCommand = listeners.NewOrderUpdateShipmentCommandListener(orderCommandHandler, email, idempotencyGuard, retryPolicy)
+	orderStoreBookingFailed = listeners.NewOrderStoreBookingFailedCommandListener(orderCommandHandler, email, idempotencyGuard, retryPolicy)
 	return &listen{
 		js: js,
 	}
@@ -47,4 +52,6 @@ func (l *listen) Listen() {
 	go subscribe.Listener(string(common_nats.OrderCreate), queueGroupName, queueGroupName+"_0", orderCreateCommand.ProcessOrderCreateCommand())
 	go subscribe.Listener(string(common_nats.OrderStatus), queueGroupName, queueGroupName+"_1", orderUpdateStatusCommand.ProcessOrderUpdateStatusCommand())
 	go subscribe.Listener(string(common_nats.StoreBooked), queueGroupName, queueGroupName+"_2", orderUpdateStoreCommand.ProcessOrderUpdateStoreCommand())
+	go subscribe.Listener(string(subjects.OrderShipmentUpdate), queueGroupName, queueGroupName+"_3", orderUpdateShipmentCommand.ProcessOrderUpdateShipmentCommand())
+	go subscribe.Listener(string(subjects.StoreBookFailed), queueGroupName, queueGroupName+"_4", orderStoreBookingFailed.ProcessOrderStoreBookingFailedCommand())
 }
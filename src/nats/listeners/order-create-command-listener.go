@@ -4,10 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"order/src/application/commands"
+	"order/src/nats/idempotency"
+	"order/src/nats/retry"
 
-	common_nats "github.com/JohnSalazar/microservices-go-common/nats"
 	common_service "github.com/JohnSalazar/microservices-go-common/services"
 	trace "github.com/JohnSalazar/microservices-go-common/trace/otel"
 	"github.com/nats-io/nats.go"
@@ -16,18 +16,21 @@ import (
 type OrderCreateCommandListener struct {
 	commandHandler *commands.OrderCommandHandler
 	email          common_service.EmailService
-	errorHelper    *common_nats.CommandErrorHelper
+	idempotency    *idempotency.IdempotencyGuard
+	retryPolicy    *retry.RetryPolicy
 }
 
 func NewOrderCreateCommandListener(
 	commandHandler *commands.OrderCommandHandler,
 	email common_service.EmailService,
-	errorHelper *common_nats.CommandErrorHelper,
+	idempotency *idempotency.IdempotencyGuard,
+	retryPolicy *retry.RetryPolicy,
 ) *OrderCreateCommandListener {
 	return &OrderCreateCommandListener{
 		commandHandler: commandHandler,
 		email:          email,
-		errorHelper:    errorHelper,
+		idempotency:    idempotency,
+		retryPolicy:    retryPolicy,
 	}
 }
 
@@ -39,14 +42,15 @@ func (c *OrderCreateCommandListener) ProcessOrderCreateCommand() nats.MsgHandler
 
 		orderCommand := &commands.CreateOrderCommand{}
 		err := json.Unmarshal(msg.Data, orderCommand)
-		if c.errorHelper.CheckUnmarshal(msg, err) == nil {
-			err = c.commandHandler.CreateOrderCommandHandler(ctx, orderCommand)
-			c.errorHelper.CheckCommandError(span, msg, err)
+		if err == nil {
+			orderCommand.Source = msg.Subject
+			err = c.idempotency.Process(ctx, msg, func(ctx context.Context) error {
+				return c.commandHandler.CreateOrderCommandHandler(ctx, orderCommand)
+			})
+		} else {
+			err = retry.Permanent(err)
 		}
 
-		err = msg.Ack()
-		if err != nil {
-			log.Printf("stan msg.Ack error: %v\n", err)
-		}
+		c.retryPolicy.Settle(span, msg, err)
 	}
 }
@@ -0,0 +1,55 @@
+package listeners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"order/src/application/commands"
+	"order/src/nats/idempotency"
+	"order/src/nats/retry"
+
+	common_service "github.com/JohnSalazar/microservices-go-common/services"
+	trace "github.com/JohnSalazar/microservices-go-common/trace/otel"
+	"github.com/nats-io/nats.go"
+)
+
+type OrderStoreBookingFailedCommandListener struct {
+	commandHandler *commands.OrderCommandHandler
+	email          common_service.EmailService
+	idempotency    *idempotency.IdempotencyGuard
+	retryPolicy    *retry.RetryPolicy
+}
+
+func NewOrderStoreBookingFailedCommandListener(
+	commandHandler *commands.OrderCommandHandler,
+	email common_service.EmailService,
+	idempotency *idempotency.IdempotencyGuard,
+	retryPolicy *retry.RetryPolicy,
+) *OrderStoreBookingFailedCommandListener {
+	return &OrderStoreBookingFailedCommandListener{
+		commandHandler: commandHandler,
+		email:          email,
+		idempotency:    idempotency,
+		retryPolicy:    retryPolicy,
+	}
+}
+
+func (c *OrderStoreBookingFailedCommandListener) ProcessOrderStoreBookingFailedCommand() nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		ctx := context.Background()
+		_, span := trace.NewSpan(ctx, fmt.Sprintf("publish.%s\n", msg.Subject))
+		defer span.End()
+
+		orderCommand := &commands.StoreBookingFailedOrderCommand{}
+		err := json.Unmarshal(msg.Data, orderCommand)
+		if err == nil {
+			err = c.idempotency.Process(ctx, msg, func(ctx context.Context) error {
+				return c.commandHandler.StoreBookingFailedOrderCommandHandler(ctx, orderCommand)
+			})
+		} else {
+			err = retry.Permanent(err)
+		}
+
+		c.retryPolicy.Settle(span, msg, err)
+	}
+}
@@ -0,0 +1,56 @@
+package listeners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"order/src/application/commands"
+	"order/src/nats/idempotency"
+	"order/src/nats/retry"
+
+	common_service "github.com/JohnSalazar/microservices-go-common/services"
+	trace "github.com/JohnSalazar/microservices-go-common/trace/otel"
+	"github.com/nats-io/nats.go"
+)
+
+type OrderUpdateShipmentCommandListener struct {
+	commandHandler *commands.OrderCommandHandler
+	email          common_service.EmailService
+	idempotency    *idempotency.IdempotencyGuard
+	retryPolicy    *retry.RetryPolicy
+}
+
+func NewOrderUpdateShipmentCommandListener(
+	commandHandler *commands.OrderCommandHandler,
+	email common_service.EmailService,
+	idempotency *idempotency.IdempotencyGuard,
+	retryPolicy *retry.RetryPolicy,
+) *OrderUpdateShipmentCommandListener {
+	return &OrderUpdateShipmentCommandListener{
+		commandHandler: commandHandler,
+		email:          email,
+		idempotency:    idempotency,
+		retryPolicy:    retryPolicy,
+	}
+}
+
+func (c *OrderUpdateShipmentCommandListener) ProcessOrderUpdateShipmentCommand() nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		ctx := context.Background()
+		_, span := trace.NewSpan(ctx, fmt.Sprintf("publish.%s\n", msg.Subject))
+		defer span.End()
+
+		orderCommand := &commands.UpdateShipmentOrderCommand{}
+		err := json.Unmarshal(msg.Data, orderCommand)
+		if err == nil {
+			orderCommand.Source = msg.Subject
+			err = c.idempotency.Process(ctx, msg, func(ctx context.Context) error {
+				return c.commandHandler.UpdateShipmentOrderCommandHandler(ctx, orderCommand)
+			})
+		} else {
+			err = retry.Permanent(err)
+		}
+
+		c.retryPolicy.Settle(span, msg, err)
+	}
+}
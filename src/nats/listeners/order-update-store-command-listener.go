@@ -4,10 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"order/src/application/commands"
+	"order/src/nats/idempotency"
+	"order/src/nats/retry"
 
-	common_nats "github.com/JohnSalazar/microservices-go-common/nats"
 	common_service "github.com/JohnSalazar/microservices-go-common/services"
 	trace "github.com/JohnSalazar/microservices-go-common/trace/otel"
 	"github.com/nats-io/nats.go"
@@ -16,18 +16,21 @@ import (
 type OrderUpdateStoreCommandListener struct {
 	commandHandler *commands.OrderCommandHandler
 	email          common_service.EmailService
-	errorHelper    *common_nats.CommandErrorHelper
+	idempotency    *idempotency.IdempotencyGuard
+	retryPolicy    *retry.RetryPolicy
 }
 
 func NewOrderUpdateStoreCommandListener(
 	commandHandler *commands.OrderCommandHandler,
 	email common_service.EmailService,
-	errorHelper *common_nats.CommandErrorHelper,
+	idempotency *idempotency.IdempotencyGuard,
+	retryPolicy *retry.RetryPolicy,
 ) *OrderUpdateStoreCommandListener {
 	return &OrderUpdateStoreCommandListener{
 		commandHandler: commandHandler,
 		email:          email,
-		errorHelper:    errorHelper,
+		idempotency:    idempotency,
+		retryPolicy:    retryPolicy,
 	}
 }
 
@@ -39,14 +42,14 @@ func (c *OrderUpdateStoreCommandListener) ProcessOrderUpdateStoreCommand() nats.
 
 		orderCommand := &commands.UpdateStoreOrderCommand{}
 		err := json.Unmarshal(msg.Data, orderCommand)
-		if c.errorHelper.CheckUnmarshal(msg, err) == nil {
-			err = c.commandHandler.UpdateStoreOrderCommandHandler(ctx, orderCommand)
-			c.errorHelper.CheckCommandError(span, msg, err)
+		if err == nil {
+			err = c.idempotency.Process(ctx, msg, func(ctx context.Context) error {
+				return c.commandHandler.UpdateStoreOrderCommandHandler(ctx, orderCommand)
+			})
+		} else {
+			err = retry.Permanent(err)
 		}
 
-		err = msg.Ack()
-		if err != nil {
-			log.Printf("stan msg.Ack error: %v\n", err)
-		}
+		c.retryPolicy.Settle(span, msg, err)
 	}
 }
@@ -0,0 +1,192 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"order/src/application/sensitive"
+	"order/src/nats/idempotency"
+	"order/src/nats/subjects"
+	"order/src/settings"
+
+	common_service "github.com/JohnSalazar/microservices-go-common/services"
+	trace "github.com/JohnSalazar/microservices-go-common/trace/otel"
+	"github.com/nats-io/nats.go"
+	trace_span "go.opentelemetry.io/otel/trace"
+)
+
+// Headers added to dead-lettered messages so they can be inspected and
+// resubmitted to the subject they came from.
+const (
+	HeaderSubject    = "Dlq-Subject"
+	HeaderStream     = "Dlq-Stream"
+	HeaderConsumer   = "Dlq-Consumer"
+	HeaderSequence   = "Dlq-Sequence"
+	HeaderDeliveries = "Dlq-Deliveries"
+	HeaderMsgID      = "Dlq-Msg-Id"
+	HeaderError      = "Dlq-Error"
+	HeaderPermanent  = "Dlq-Permanent"
+	HeaderFailedAt   = "Dlq-Failed-At"
+)
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as one that retrying cannot fix, e.g. a payload that
+// does not unmarshal.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// RetryPolicy settles a processed message: it acks successes, naks transient
+// failures with exponential backoff and moves permanent failures, or those
+// out of deliveries, to the dead-letter stream.
+type RetryPolicy struct {
+	settings  *settings.RetrySettings
+	js        nats.JetStreamContext
+	email     common_service.EmailService
+	permanent func(err error) bool
+}
+
+func NewRetryPolicy(
+	settings *settings.RetrySettings,
+	js nats.JetStreamContext,
+	email common_service.EmailService,
+	permanent func(err error) bool,
+) *RetryPolicy {
+	return &RetryPolicy{
+		settings:  settings,
+		js:        js,
+		email:     email,
+		permanent: permanent,
+	}
+}
+
+// Settle acks, naks or dead-letters msg once its command ran. Support is
+// only emailed when a message is dead-lettered.
+func (p *RetryPolicy) Settle(span trace_span.Span, msg *nats.Msg, err error) {
+	if err == nil {
+		p.ack(msg)
+		return
+	}
+
+	// Another delivery of the command is still running; this one waits for it
+	// and does not count as a failure.
+	if errors.Is(err, idempotency.ErrCommandInProgress) {
+		idempotency.Ack(msg, err)
+		return
+	}
+
+	msgErr := fmt.Sprintf("error processing %s: %s", msg.Subject, err.Error())
+	trace.FailSpan(span, msgErr)
+	log.Println(msgErr)
+
+	deliveries := uint64(1)
+	metadata, metadataErr := msg.Metadata()
+	if metadataErr == nil {
+		deliveries = metadata.NumDelivered
+	}
+
+	permanent := p.isPermanent(err)
+	if !permanent && deliveries < uint64(p.settings.MaxDeliveries) {
+		delay := p.Backoff(deliveries)
+		nakErr := msg.NakWithDelay(delay)
+		if nakErr != nil {
+			log.Printf("nats msg.NakWithDelay error: %v\n", nakErr)
+		}
+		return
+	}
+
+	deadLetterErr := p.deadLetter(msg, metadata, deliveries, permanent, err)
+	if deadLetterErr != nil {
+		// Keep the message in its stream rather than lose it.
+		log.Printf("%s dead letter error: %v\n", msg.Subject, deadLetterErr)
+		nakErr := msg.NakWithDelay(p.Backoff(deliveries))
+		if nakErr != nil {
+			log.Printf("nats msg.NakWithDelay error: %v\n", nakErr)
+		}
+		return
+	}
+
+	p.ack(msg)
+}
+
+// Backoff returns the delay before delivery number deliveries+1.
+func (p *RetryPolicy) Backoff(deliveries uint64) time.Duration {
+	initial := time.Duration(p.settings.InitialBackoffSeconds) * time.Second
+	backoff := time.Duration(math.Pow(2, float64(deliveries-1))) * initial
+
+	maxBackoff := time.Duration(p.settings.MaxBackoffSeconds) * time.Second
+	if backoff <= 0 || backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	return backoff
+}
+
+func (p *RetryPolicy) isPermanent(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return true
+	}
+
+	return p.permanent != nil && p.permanent(err)
+}
+
+func (p *RetryPolicy) deadLetter(msg *nats.Msg, metadata *nats.MsgMetadata, deliveries uint64, permanent bool, err error) error {
+	deadLetter := nats.NewMsg(string(subjects.OrderDeadLetter))
+	// Dead letters are read by operators, so card data is dropped; an
+	// OrderCreate resubmitted from here needs the card to be sent again.
+	deadLetter.Data, _ = sensitive.Redact(msg.Data)
+	deadLetter.Header.Set(HeaderSubject, msg.Subject)
+	deadLetter.Header.Set(HeaderDeliveries, strconv.FormatUint(deliveries, 10))
+	deadLetter.Header.Set(HeaderError, err.Error())
+	deadLetter.Header.Set(HeaderPermanent, strconv.FormatBool(permanent))
+	deadLetter.Header.Set(HeaderFailedAt, time.Now().UTC().Format(time.RFC3339))
+
+	if metadata != nil {
+		deadLetter.Header.Set(HeaderStream, metadata.Stream)
+		deadLetter.Header.Set(HeaderConsumer, metadata.Consumer)
+		deadLetter.Header.Set(HeaderSequence, strconv.FormatUint(metadata.Sequence.Stream, 10))
+	}
+
+	if msg.Header != nil {
+		if ID := msg.Header.Get(nats.MsgIdHdr); len(ID) > 0 {
+			deadLetter.Header.Set(HeaderMsgID, ID)
+		}
+	}
+
+	_, publishErr := p.js.PublishMsg(deadLetter)
+	if publishErr != nil {
+		return publishErr
+	}
+
+	go p.email.SendSupportMessage(fmt.Sprintf("%s command moved to %s after %d deliveries: %s",
+		msg.Subject, p.settings.DeadLetterStream, deliveries, err.Error()))
+
+	return nil
+}
+
+func (p *RetryPolicy) ack(msg *nats.Msg) {
+	err := msg.Ack()
+	if err != nil {
+		log.Printf("stan msg.Ack error: %v\n", err)
+	}
+}
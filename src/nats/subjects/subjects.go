@@ -0,0 +1,68 @@
+package subjects
+
+import (
+	common_nats "github.com/JohnSalazar/microservices-go-common/nats"
+)
+
+// Each group is captured by a stream this service creates in main: order
+// subjects by "order", store subjects by "store2" next to StoreBooked,
+// payment subjects by "payment2" and customer subjects by "customer2". None
+// of them is added to a stream another service owns, so the stream
+// definitions can not overlap.
+const (
+	OrderStatusRejected common_nats.OrderSubject = "order:status:rejected"
+	OrderShipmentUpdate common_nats.OrderSubject = "order:shipment:update"
+)
+
+const (
+	PaymentAdjust      common_nats.PaymentSubject = "payment:adjust"
+	PaymentRefund      common_nats.PaymentSubject = "payment:refund"
+	PaymentReauthorize common_nats.PaymentSubject = "payment:reauthorize"
+)
+
+const (
+	StoreRelease        common_nats.StoreSubject = "store:release"
+	StoreRestock        common_nats.StoreSubject = "store:restock"
+	StorePaymentReverse common_nats.StoreSubject = "store:payment:reverse"
+	// StoreBookFailed is the store service's reply when it cannot book the
+	// items requested by StoreBook.
+	StoreBookFailed common_nats.StoreSubject = "store:book:failed"
+)
+
+const (
+	CustomerNotify common_nats.CustomerSubject = "customer:notify"
+)
+
+// OrderDeadLetter receives commands that could not be processed. It has its
+// own stream so dead letters are kept apart from live traffic.
+const OrderDeadLetter common_nats.OrderSubject = "order:dlq"
+
+func GetOrderSubjects() []string {
+	return []string{
+		string(OrderStatusRejected),
+		string(OrderShipmentUpdate),
+	}
+}
+
+func GetPaymentSubjects() []string {
+	return []string{
+		string(PaymentAdjust),
+		string(PaymentRefund),
+		string(PaymentReauthorize),
+	}
+}
+
+func GetStoreSubjects() []string {
+	return []string{
+		string(StoreRelease),
+		string(StoreRestock),
+		string(StorePaymentReverse),
+		string(StoreBookFailed),
+	}
+}
+
+func GetCustomerSubjects() []string {
+	return []string{
+		string(CustomerNotify),
+	}
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+type LockRepository interface {
+	TryAcquire(ctx context.Context, name string, owner string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name string, owner string) error
+}
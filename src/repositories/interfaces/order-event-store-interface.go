@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderEventStoreRepository interface {
+	Append(ctx context.Context, event *models.StoredEvent) error
+	FindByOrderID(ctx context.Context, orderID primitive.ObjectID, fromVersion uint, until time.Time) ([]*models.StoredEvent, error)
+	SaveSnapshot(ctx context.Context, snapshot *models.OrderSnapshot) error
+	FindLatestSnapshot(ctx context.Context, orderID primitive.ObjectID, until time.Time) (*models.OrderSnapshot, error)
+}
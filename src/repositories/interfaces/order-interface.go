@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"order/src/models"
 
@@ -9,9 +10,11 @@ import (
 )
 
 type OrderRepository interface {
-	GetAll(ctx context.Context, customerID primitive.ObjectID) ([]*models.Order, error)
+	GetAll(ctx context.Context, customerID primitive.ObjectID, queryOptions *models.OrderQueryOptions) (*models.OrderPage, error)
+	Search(ctx context.Context, queryOptions *models.OrderQueryOptions) (*models.OrderPage, error)
 	FindByCustomerID(ctx context.Context, customerID primitive.ObjectID) (*models.Order, error)
 	FindByID(ctx context.Context, ID primitive.ObjectID) (*models.Order, error)
+	FindExpired(ctx context.Context, status uint, before time.Time, limit int64) ([]*models.Order, error)
 	Create(ctx context.Context, order *models.Order) (*models.Order, error)
 	Update(ctx context.Context, order *models.Order) (*models.Order, error)
 	Delete(ctx context.Context, ID primitive.ObjectID) error
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderSagaRepository interface {
+	FindByOrderID(ctx context.Context, orderID primitive.ObjectID) (*models.OrderSaga, error)
+	FindStalled(ctx context.Context, before time.Time, limit int64) ([]*models.OrderSaga, error)
+	Create(ctx context.Context, saga *models.OrderSaga) error
+	Update(ctx context.Context, saga *models.OrderSaga) error
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OutboxRepository interface {
+	Add(ctx context.Context, message *models.OutboxMessage) error
+	FindPending(ctx context.Context, now time.Time, limit int64) ([]*models.OutboxMessage, error)
+	FindBackingOff(ctx context.Context, now time.Time) ([]primitive.ObjectID, error)
+	MarkPublished(ctx context.Context, ID primitive.ObjectID) error
+	MarkFailed(ctx context.Context, ID primitive.ObjectID, lastError string, nextAttemptAt time.Time) error
+	Scrub(ctx context.Context, ID primitive.ObjectID, data string) error
+}
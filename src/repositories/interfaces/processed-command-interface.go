@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"order/src/models"
+)
+
+type ProcessedCommandRepository interface {
+	Begin(ctx context.Context, key string, subject string, lockedUntil time.Time, expiresAt time.Time) (bool, error)
+	Complete(ctx context.Context, key string, status string, commandError string) error
+	FindByKey(ctx context.Context, key string) (*models.ProcessedCommand, error)
+}
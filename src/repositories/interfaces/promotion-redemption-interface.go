@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PromotionRedemptionRepository interface {
+	Usage(ctx context.Context, code string, customerID primitive.ObjectID) (uint, error)
+	Redeem(ctx context.Context, redemption *models.PromotionRedemption, limit uint) (bool, error)
+	Release(ctx context.Context, orderID primitive.ObjectID) error
+}
@@ -0,0 +1,7 @@
+package interfaces
+
+import "context"
+
+type Transaction interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
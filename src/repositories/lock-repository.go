@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LockRepository struct {
+	database *mongo.Database
+}
+
+func NewLockRepository(
+	database *mongo.Database,
+) *LockRepository {
+	return &LockRepository{
+		database: database,
+	}
+}
+
+func (r *LockRepository) collection() *mongo.Collection {
+	return r.database.Collection("locks")
+}
+
+func (r *LockRepository) TryAcquire(ctx context.Context, name string, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"owner": owner},
+			bson.M{"expires_at": bson.M{"$lt": now}},
+		},
+	}
+
+	fields := bson.M{
+		"owner":      owner,
+		"expires_at": now.Add(ttl),
+	}
+
+	updateOptions := options.Update().SetUpsert(true)
+	_, err := r.collection().UpdateOne(ctx, filter, bson.M{"$set": fields}, updateOptions)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *LockRepository) Release(ctx context.Context, name string, owner string) error {
+	_, err := r.collection().DeleteOne(ctx, bson.M{"_id": name, "owner": owner})
+
+	return err
+}
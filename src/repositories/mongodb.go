@@ -1,10 +1,12 @@
 package repositories
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/JohnSalazar/microservices-go-common/config"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -29,3 +31,62 @@ func NewMongoDatabase(
 ) *mongo.Database {
 	return client.Database(config.MongoDB.Database)
 }
+
+// outboxRetentionSeconds keeps published outbox messages for a week.
+const outboxRetentionSeconds = 7 * 24 * 60 * 60
+
+func CreateIndexes(ctx context.Context, database *mongo.Database) error {
+	indexes := map[string][]mongo.IndexModel{
+		"order_events": {
+			{
+				Keys:    bson.D{{Key: "order_id", Value: 1}, {Key: "version", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+		"orders": {
+			{
+				Keys: bson.D{{Key: "status", Value: 1}, {Key: "status_at", Value: 1}},
+			},
+		},
+		"outbox": {
+			{
+				Keys: bson.D{{Key: "published", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+			},
+			{
+				Keys:    bson.D{{Key: "published_at", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(outboxRetentionSeconds),
+			},
+		},
+		"processed_commands": {
+			{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(0),
+			},
+		},
+		"promotion_redemptions": {
+			{
+				Keys: bson.D{{Key: "order_id", Value: 1}, {Key: "released", Value: 1}},
+			},
+		},
+		"order_sagas": {
+			{
+				Keys: bson.D{{Key: "status", Value: 1}, {Key: "steps.status", Value: 1}, {Key: "steps.last_attempt_at", Value: 1}},
+			},
+		},
+		"order_snapshots": {
+			{
+				Keys:    bson.D{{Key: "order_id", Value: 1}, {Key: "version", Value: -1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+	}
+
+	for collection, models := range indexes {
+		_, err := database.Collection(collection).Indexes().CreateMany(ctx, models)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
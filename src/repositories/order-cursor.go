@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type orderCursor struct {
+	ID    primitive.ObjectID `json:"id"`
+	Value json.RawMessage    `json:"value"`
+}
+
+type orderSortField struct {
+	field string
+	value func(order *models.Order) interface{}
+}
+
+var orderSortFields = map[string]orderSortField{
+	"created_at": {"created_at", func(order *models.Order) interface{} { return order.CreatedAt }},
+	"status_at":  {"status_at", func(order *models.Order) interface{} { return order.StatusAt }},
+	"status":     {"status", func(order *models.Order) interface{} { return order.Status }},
+	"sum":        {"sum.amount", func(order *models.Order) interface{} { return order.Sum.Amount }},
+}
+
+func encodeOrderCursor(sortField string, order *models.Order) (string, error) {
+	value, err := json.Marshal(orderSortFields[sortField].value(order))
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(&orderCursor{ID: order.ID, Value: value})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeOrderCursor(sortField string, cursor string) (primitive.ObjectID, interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return primitive.NilObjectID, nil, models.ErrInvalidCursor
+	}
+
+	object := &orderCursor{}
+	if err := json.Unmarshal(data, object); err != nil {
+		return primitive.NilObjectID, nil, models.ErrInvalidCursor
+	}
+
+	var value interface{}
+	switch sortField {
+	case "created_at", "status_at":
+		var at time.Time
+		err = json.Unmarshal(object.Value, &at)
+		value = at
+	case "status":
+		var status uint
+		err = json.Unmarshal(object.Value, &status)
+		value = status
+	case "sum":
+		var sum int64
+		err = json.Unmarshal(object.Value, &sum)
+		value = sum
+	default:
+		return primitive.NilObjectID, nil, models.ErrInvalidSortField
+	}
+	if err != nil {
+		return primitive.NilObjectID, nil, models.ErrInvalidCursor
+	}
+
+	return object.ID, value, nil
+}
+
+func cursorFilter(sortField string, sortAsc bool, ID primitive.ObjectID, value interface{}) bson.M {
+	sortField = orderSortFields[sortField].field
+
+	operator := "$lt"
+	if sortAsc {
+		operator = "$gt"
+	}
+
+	return bson.M{
+		"$or": bson.A{
+			bson.M{sortField: bson.M{operator: value}},
+			bson.M{sortField: value, "_id": bson.M{operator: ID}},
+		},
+	}
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OrderEventStoreRepository struct {
+	database *mongo.Database
+}
+
+func NewOrderEventStoreRepository(
+	database *mongo.Database,
+) *OrderEventStoreRepository {
+	return &OrderEventStoreRepository{
+		database: database,
+	}
+}
+
+func (r *OrderEventStoreRepository) eventsCollection() *mongo.Collection {
+	return r.database.Collection("order_events")
+}
+
+func (r *OrderEventStoreRepository) snapshotsCollection() *mongo.Collection {
+	return r.database.Collection("order_snapshots")
+}
+
+func (r *OrderEventStoreRepository) Append(ctx context.Context, event *models.StoredEvent) error {
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.eventsCollection().InsertOne(ctx, event)
+
+	return err
+}
+
+func (r *OrderEventStoreRepository) FindByOrderID(ctx context.Context, orderID primitive.ObjectID, fromVersion uint, until time.Time) ([]*models.StoredEvent, error) {
+	filter := bson.M{
+		"order_id": orderID,
+		"version":  bson.M{"$gte": fromVersion},
+	}
+
+	if !until.IsZero() {
+		filter["occurred_at"] = bson.M{"$lte": until}
+	}
+
+	findOptions := options.FindOptions{}
+	findOptions.SetSort(bson.M{"version": 1})
+
+	cursor, err := r.eventsCollection().Find(ctx, filter, &findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	storedEvents := []*models.StoredEvent{}
+	err = cursor.All(ctx, &storedEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	return storedEvents, nil
+}
+
+func (r *OrderEventStoreRepository) SaveSnapshot(ctx context.Context, snapshot *models.OrderSnapshot) error {
+	filter := bson.M{
+		"order_id": snapshot.OrderID,
+		"version":  snapshot.Version,
+	}
+
+	replaceOptions := options.Replace().SetUpsert(true)
+	_, err := r.snapshotsCollection().ReplaceOne(ctx, filter, snapshot, replaceOptions)
+
+	return err
+}
+
+func (r *OrderEventStoreRepository) FindLatestSnapshot(ctx context.Context, orderID primitive.ObjectID, until time.Time) (*models.OrderSnapshot, error) {
+	filter := bson.M{"order_id": orderID}
+
+	if !until.IsZero() {
+		filter["occurred_at"] = bson.M{"$lte": until}
+	}
+
+	findOneOptions := options.FindOneOptions{}
+	findOneOptions.SetSort(bson.M{"version": -1})
+
+	snapshot := &models.OrderSnapshot{}
+	err := r.snapshotsCollection().FindOne(ctx, filter, &findOneOptions).Decode(snapshot)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
@@ -103,10 +103,123 @@ func (r *OrderRepository) findOneAndUpdate(ctx context.Context, filter interface
 	return result
 }
 
-func (r *OrderRepository) GetAll(ctx context.Context, customerID primitive.ObjectID) ([]*models.Order, error) {
-	filter := bson.M{"customer_id": customerID}
+func (r *OrderRepository) findPage(ctx context.Context, filter bson.M, queryOptions *models.OrderQueryOptions) (*models.OrderPage, error) {
+	sortField := queryOptions.SortField
+	if len(sortField) == 0 {
+		sortField = "created_at"
+	}
+
+	if _, ok := orderSortFields[sortField]; !ok {
+		return nil, models.ErrInvalidSortField
+	}
+
+	limit := queryOptions.Limit
+	if limit <= 0 {
+		limit = models.OrderQueryDefaultLimit
+	}
+
+	if limit > models.OrderQueryMaxLimit {
+		limit = models.OrderQueryMaxLimit
+	}
+
+	filter["deleted"] = false
+
+	if !queryOptions.CustomerID.IsZero() {
+		filter["customer_id"] = queryOptions.CustomerID
+	}
+
+	if queryOptions.ProductID != uuid.Nil {
+		filter["products._id"] = queryOptions.ProductID.String()
+	}
+
+	if len(queryOptions.Statuses) > 0 {
+		filter["status"] = bson.M{"$in": queryOptions.Statuses}
+	}
+
+	createdAt := bson.M{}
+	if !queryOptions.CreatedFrom.IsZero() {
+		createdAt["$gte"] = queryOptions.CreatedFrom
+	}
+
+	if !queryOptions.CreatedTo.IsZero() {
+		createdAt["$lte"] = queryOptions.CreatedTo
+	}
+
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+
+	total, err := r.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(queryOptions.Cursor) > 0 {
+		ID, value, err := decodeOrderCursor(sortField, queryOptions.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		filter["$and"] = bson.A{cursorFilter(sortField, queryOptions.SortAsc, ID, value)}
+	}
+
+	direction := -1
+	if queryOptions.SortAsc {
+		direction = 1
+	}
+
+	findOptions := options.FindOptions{}
+	findOptions.SetSort(bson.D{{Key: orderSortFields[sortField].field, Value: direction}, {Key: "_id", Value: direction}})
+	findOptions.SetLimit(limit + 1)
+
+	cursor, err := r.collection().Find(ctx, filter, &findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	orders := []*models.Order{}
+
+	for cursor.Next(ctx) {
+		object := map[string]interface{}{}
+
+		err = cursor.Decode(object)
+		if err != nil {
+			return nil, err
+		}
+
+		order, err := r.mapOrder(object)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, order)
+	}
 
-	return r.find(ctx, filter)
+	page := &models.OrderPage{
+		Orders: orders,
+		Total:  total,
+	}
+
+	if int64(len(orders)) > limit {
+		page.Orders = orders[:limit]
+		page.NextCursor, err = encodeOrderCursor(sortField, page.Orders[limit-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+func (r *OrderRepository) GetAll(ctx context.Context, customerID primitive.ObjectID, queryOptions *models.OrderQueryOptions) (*models.OrderPage, error) {
+	queryOptions.CustomerID = customerID
+
+	return r.findPage(ctx, bson.M{}, queryOptions)
+}
+
+func (r *OrderRepository) Search(ctx context.Context, queryOptions *models.OrderQueryOptions) (*models.OrderPage, error) {
+	return r.findPage(ctx, bson.M{}, queryOptions)
 }
 
 func (r *OrderRepository) FindByCustomerID(ctx context.Context, customerID primitive.ObjectID) (*models.Order, error) {
@@ -121,20 +234,74 @@ func (r *OrderRepository) FindByID(ctx context.Context, ID primitive.ObjectID) (
 	return r.findOne(ctx, filter)
 }
 
+func (r *OrderRepository) FindExpired(ctx context.Context, status uint, before time.Time, limit int64) ([]*models.Order, error) {
+	filter := bson.M{
+		"deleted":   false,
+		"status":    status,
+		"status_at": bson.M{"$lt": before},
+	}
+
+	findOptions := options.FindOptions{}
+	findOptions.SetSort(bson.D{{Key: "status_at", Value: 1}})
+	findOptions.SetLimit(limit)
+
+	cursor, err := r.collection().Find(ctx, filter, &findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	orders := []*models.Order{}
+
+	for cursor.Next(ctx) {
+		object := map[string]interface{}{}
+
+		err = cursor.Decode(object)
+		if err != nil {
+			return nil, err
+		}
+
+		order, err := r.mapOrder(object)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, cursor.Err()
+}
+
 func (r *OrderRepository) Create(ctx context.Context, order *models.Order) (*models.Order, error) {
 	products := r.mapOrderProducts(order.Products)
+	lines := r.mapOrderLines(order.Lines)
+	taxLines := r.mapOrderTaxLines(order.TaxLines)
+	promotions := r.mapOrderPromotions(order.Promotions)
+	history := r.mapOrderStatusHistory(order.History)
 
 	fields := bson.M{
-		"_id":         order.ID,
-		"customer_id": order.CustomerID,
-		"products":    products,
-		"sum":         order.Sum,
-		"discount":    order.Discount,
-		"status":      order.Status,
-		"status_at":   order.StatusAt,
-		"created_at":  time.Now().UTC(),
-		"version":     0,
-		"deleted":     false,
+		"_id":              order.ID,
+		"customer_id":      order.CustomerID,
+		"products":         products,
+		"lines":            lines,
+		"sum":              order.Sum,
+		"discount":         order.Discount,
+		"currency":         order.Currency,
+		"exchange_rate":    order.ExchangeRate,
+		"promotions":       promotions,
+		"region":           order.Region,
+		"shipping_address": order.ShippingAddress,
+		"billing_address":  order.BillingAddress,
+		"delivery_method":  order.DeliveryMethod,
+		"shipping_cost":    order.ShippingCost,
+		"tax_lines":        taxLines,
+		"tax":              order.Tax,
+		"status":           order.Status,
+		"status_at":        order.StatusAt,
+		"status_history":   history,
+		"created_at":       time.Now().UTC(),
+		"version":          0,
+		"deleted":          false,
 	}
 
 	_, err := r.collection().InsertOne(ctx, fields)
@@ -151,16 +318,32 @@ func (r *OrderRepository) Update(ctx context.Context, order *models.Order) (*mod
 
 	products := r.mapOrderProducts(order.Products)
 	stores := r.mapOrderStores(order.Stores)
+	lines := r.mapOrderLines(order.Lines)
+	taxLines := r.mapOrderTaxLines(order.TaxLines)
+	promotions := r.mapOrderPromotions(order.Promotions)
+	canceledItems := r.mapOrderCanceledItems(order.CanceledItems)
+	returns := r.mapOrderReturns(order.Returns)
+	shipments := r.mapOrderShipments(order.Shipments)
+	history := r.mapOrderStatusHistory(order.History)
 
 	fields := bson.M{
-		"products":   products,
-		"stores":     stores,
-		"sum":        order.Sum,
-		"discount":   order.Discount,
-		"status":     order.Status,
-		"status_at":  order.StatusAt,
-		"updated_at": order.UpdatedAt,
-		"version":    order.Version,
+		"products":           products,
+		"lines":              lines,
+		"stores":             stores,
+		"sum":                order.Sum,
+		"discount":           order.Discount,
+		"promotions":         promotions,
+		"tax_lines":          taxLines,
+		"tax":                order.Tax,
+		"canceled_items":     canceledItems,
+		"returns":            returns,
+		"shipments":          shipments,
+		"fulfillment_status": order.FulfillmentStatus,
+		"status":             order.Status,
+		"status_at":          order.StatusAt,
+		"status_history":     history,
+		"updated_at":         order.UpdatedAt,
+		"version":            order.Version,
 	}
 
 	filter := r.filterUpdate(order)
@@ -197,6 +380,55 @@ func (r *OrderRepository) Delete(ctx context.Context, ID primitive.ObjectID) err
 	return nil
 }
 
+// MigrateMoney rewrites orders whose amounts are still stored as plain
+// decimal numbers into minor units of the default currency.
+func (r *OrderRepository) MigrateMoney(ctx context.Context) (int64, error) {
+	numeric := bson.M{"$type": bson.A{"double", "int", "long", "decimal"}}
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"sum": numeric},
+			bson.M{"discount": numeric},
+			bson.M{"products.Price": numeric},
+		},
+	}
+
+	cursor, err := r.collection().Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var migrated int64
+	for cursor.Next(ctx) {
+		object := map[string]interface{}{}
+
+		err = cursor.Decode(object)
+		if err != nil {
+			return migrated, err
+		}
+
+		order, err := r.mapOrder(object)
+		if err != nil {
+			return migrated, err
+		}
+
+		fields := bson.M{
+			"products": r.mapOrderProducts(order.Products),
+			"sum":      order.Sum,
+			"discount": order.Discount,
+		}
+
+		_, err = r.collection().UpdateByID(ctx, order.ID, bson.M{"$set": fields})
+		if err != nil {
+			return migrated, err
+		}
+
+		migrated++
+	}
+
+	return migrated, cursor.Err()
+}
+
 func (r *OrderRepository) filterUpdate(order *models.Order) interface{} {
 	filter := bson.M{
 		"_id":     order.ID,
@@ -248,9 +480,152 @@ func (r *OrderRepository) mapOrder(object map[string]interface{}) (*models.Order
 		order.Stores = stores
 	}
 
+	if object["lines"] != nil {
+		var lines []*models.OrderLine
+		listLines := object["lines"].(primitive.A)
+		for _, line := range listLines {
+			line, err := r.mapLineFromInterfaceToModel(line.(map[string]interface{}))
+			if err != nil {
+				return nil, err
+			}
+
+			lines = append(lines, line)
+		}
+		order.Lines = lines
+	}
+
+	if object["tax_lines"] != nil {
+		var taxLines []*models.TaxLine
+		listTaxLines := object["tax_lines"].(primitive.A)
+		for _, taxLine := range listTaxLines {
+			taxLine, err := r.mapTaxLineFromInterfaceToModel(taxLine.(map[string]interface{}))
+			if err != nil {
+				return nil, err
+			}
+
+			taxLines = append(taxLines, taxLine)
+		}
+		order.TaxLines = taxLines
+	}
+
+	if object["canceled_items"] != nil {
+		var canceledItems []*models.CanceledItem
+		listCanceledItems := object["canceled_items"].(primitive.A)
+		for _, canceledItem := range listCanceledItems {
+			canceledItem, err := r.mapCanceledItemFromInterfaceToModel(canceledItem.(map[string]interface{}))
+			if err != nil {
+				return nil, err
+			}
+
+			canceledItems = append(canceledItems, canceledItem)
+		}
+		order.CanceledItems = canceledItems
+	}
+
+	if object["shipments"] != nil {
+		var shipments []*models.Shipment
+		listShipments := object["shipments"].(primitive.A)
+		for _, shipment := range listShipments {
+			shipment, err := r.mapShipmentFromInterfaceToModel(shipment.(map[string]interface{}))
+			if err != nil {
+				return nil, err
+			}
+
+			shipments = append(shipments, shipment)
+		}
+		order.Shipments = shipments
+	}
+
+	if fulfillmentStatus, ok := object["fulfillment_status"].(string); ok {
+		order.FulfillmentStatus = fulfillmentStatus
+	}
+
+	if object["returns"] != nil {
+		var returns []*models.ReturnRequest
+		listReturns := object["returns"].(primitive.A)
+		for _, returnRequest := range listReturns {
+			returnRequest, err := r.mapReturnFromInterfaceToModel(returnRequest.(map[string]interface{}))
+			if err != nil {
+				return nil, err
+			}
+
+			returns = append(returns, returnRequest)
+		}
+		order.Returns = returns
+	}
+
+	if object["shipping_address"] != nil {
+		order.ShippingAddress = r.mapAddressFromInterfaceToModel(object["shipping_address"].(map[string]interface{}))
+	}
+
+	if object["billing_address"] != nil {
+		order.BillingAddress = r.mapAddressFromInterfaceToModel(object["billing_address"].(map[string]interface{}))
+	}
+
+	if object["delivery_method"] != nil {
+		order.DeliveryMethod = object["delivery_method"].(string)
+	}
+
+	if object["shipping_cost"] != nil {
+		shippingCost, err := r.mapMoney(object["shipping_cost"])
+		if err != nil {
+			return nil, err
+		}
+		order.ShippingCost = shippingCost
+	}
+
+	if object["exchange_rate"] != nil {
+		exchangeRate, err := r.mapExchangeRateFromInterfaceToModel(object["exchange_rate"].(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+		order.ExchangeRate = exchangeRate
+	}
+
+	if object["status_history"] != nil {
+		var history []*models.StatusHistory
+		listHistory := object["status_history"].(primitive.A)
+		for _, entry := range listHistory {
+			entry := r.mapStatusHistoryFromInterfaceToModel(entry.(map[string]interface{}))
+			history = append(history, entry)
+		}
+		order.History = history
+	}
+
 	return &order, nil
 }
 
+func (r *OrderRepository) mapExchangeRateFromInterfaceToModel(object map[string]interface{}) (*models.ExchangeRate, error) {
+	jsonStr, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	var exchangeRate models.ExchangeRate
+	if err := json.Unmarshal(jsonStr, &exchangeRate); err != nil {
+		return nil, err
+	}
+
+	return &exchangeRate, nil
+}
+
+func (r *OrderRepository) mapAddressFromInterfaceToModel(object map[string]interface{}) *models.Address {
+	address := models.Address{}
+
+	address.Name, _ = object["name"].(string)
+	address.Street, _ = object["street"].(string)
+	address.Number, _ = object["number"].(string)
+	address.Complement, _ = object["complement"].(string)
+	address.District, _ = object["district"].(string)
+	address.City, _ = object["city"].(string)
+	address.State, _ = object["state"].(string)
+	address.PostalCode, _ = object["postal_code"].(string)
+	address.Country, _ = object["country"].(string)
+	address.Phone, _ = object["phone"].(string)
+
+	return &address
+}
+
 func (r *OrderRepository) mapProductFromInterfaceToModel(object map[string]interface{}) (*models.Product, error) {
 	jsonStr, err := json.Marshal(object)
 	if err != nil {
@@ -290,6 +665,209 @@ func (r *OrderRepository) mapStoreFromInterfaceToModel(object map[string]interfa
 	return &store, nil
 }
 
+func (r *OrderRepository) mapLineFromInterfaceToModel(object map[string]interface{}) (*models.OrderLine, error) {
+	line := models.OrderLine{}
+
+	ProductID, err := uuid.Parse(object["product_id"].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	line.ProductID = ProductID
+	line.Quantity = uint(toInt64(object["quantity"]))
+
+	if currency, ok := object["currency"].(string); ok {
+		line.Currency = currency
+	}
+
+	if category, ok := object["category"].(string); ok {
+		line.Category = category
+	}
+
+	line.UnitPrice, err = r.mapMoney(object["unit_price"])
+	if err != nil {
+		return nil, err
+	}
+
+	line.Total, err = r.mapMoney(object["total"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &line, nil
+}
+
+func (r *OrderRepository) mapTaxLineFromInterfaceToModel(object map[string]interface{}) (*models.TaxLine, error) {
+	taxLine := models.TaxLine{}
+
+	ProductID, err := uuid.Parse(object["product_id"].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	taxLine.ProductID = ProductID
+	taxLine.Region, _ = object["region"].(string)
+	taxLine.Category, _ = object["category"].(string)
+	taxLine.Name, _ = object["name"].(string)
+	taxLine.Rate, _ = object["rate"].(string)
+
+	taxLine.Taxable, err = r.mapMoney(object["taxable"])
+	if err != nil {
+		return nil, err
+	}
+
+	taxLine.Amount, err = r.mapMoney(object["amount"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &taxLine, nil
+}
+
+func (r *OrderRepository) mapCanceledItemFromInterfaceToModel(object map[string]interface{}) (*models.CanceledItem, error) {
+	canceledItem := models.CanceledItem{}
+
+	ProductID, err := uuid.Parse(object["product_id"].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	canceledItem.ProductID = ProductID
+	canceledItem.Quantity = uint(toInt64(object["quantity"]))
+	canceledItem.Source, _ = object["source"].(string)
+
+	if canceledAt, ok := object["canceled_at"].(primitive.DateTime); ok {
+		canceledItem.CanceledAt = canceledAt.Time().UTC()
+	}
+
+	canceledItem.Amount, err = r.mapMoney(object["amount"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &canceledItem, nil
+}
+
+func (r *OrderRepository) mapReturnFromInterfaceToModel(object map[string]interface{}) (*models.ReturnRequest, error) {
+	returnRequest := models.ReturnRequest{}
+
+	returnRequest.ID = object["_id"].(primitive.ObjectID)
+	returnRequest.Status, _ = object["status"].(string)
+	returnRequest.Note, _ = object["note"].(string)
+	returnRequest.RequestedBy, _ = object["requested_by"].(string)
+	returnRequest.UpdatedBy, _ = object["updated_by"].(string)
+
+	if requestedAt, ok := object["requested_at"].(primitive.DateTime); ok {
+		returnRequest.RequestedAt = requestedAt.Time().UTC()
+	}
+
+	if updatedAt, ok := object["updated_at"].(primitive.DateTime); ok {
+		returnRequest.UpdatedAt = updatedAt.Time().UTC()
+	}
+
+	refund, err := r.mapMoney(object["refund"])
+	if err != nil {
+		return nil, err
+	}
+	returnRequest.Refund = refund
+
+	if object["items"] != nil {
+		listItems := object["items"].(primitive.A)
+		for _, item := range listItems {
+			item := item.(map[string]interface{})
+
+			ProductID, err := uuid.Parse(item["product_id"].(string))
+			if err != nil {
+				return nil, err
+			}
+
+			returnItem := &models.ReturnItem{
+				ProductID: ProductID,
+				Quantity:  uint(toInt64(item["quantity"])),
+			}
+			returnItem.Reason, _ = item["reason"].(string)
+			returnItem.Comment, _ = item["comment"].(string)
+
+			returnRequest.Items = append(returnRequest.Items, returnItem)
+		}
+	}
+
+	return &returnRequest, nil
+}
+
+func (r *OrderRepository) mapShipmentFromInterfaceToModel(object map[string]interface{}) (*models.Shipment, error) {
+	shipment := models.Shipment{}
+
+	StoreID, err := uuid.Parse(object["store_id"].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	shipment.ID = object["_id"].(primitive.ObjectID)
+	shipment.StoreID = StoreID
+	shipment.Status, _ = object["status"].(string)
+	shipment.Carrier, _ = object["carrier"].(string)
+	shipment.TrackingNumber, _ = object["tracking_number"].(string)
+
+	if object["product_ids"] != nil {
+		for _, productID := range object["product_ids"].(primitive.A) {
+			ProductID, err := uuid.Parse(productID.(string))
+			if err != nil {
+				return nil, err
+			}
+
+			shipment.ProductIDs = append(shipment.ProductIDs, ProductID)
+		}
+	}
+
+	dates := map[string]*time.Time{
+		"created_at":   &shipment.CreatedAt,
+		"packed_at":    &shipment.PackedAt,
+		"shipped_at":   &shipment.ShippedAt,
+		"delivered_at": &shipment.DeliveredAt,
+		"updated_at":   &shipment.UpdatedAt,
+	}
+
+	for field, date := range dates {
+		if value, ok := object[field].(primitive.DateTime); ok {
+			*date = value.Time().UTC()
+		}
+	}
+
+	return &shipment, nil
+}
+
+func (r *OrderRepository) mapMoney(object interface{}) (models.Money, error) {
+	money := models.Money{}
+
+	jsonStr, err := json.Marshal(object)
+	if err != nil {
+		return money, err
+	}
+
+	err = json.Unmarshal(jsonStr, &money)
+
+	return money, err
+}
+
+func (r *OrderRepository) mapStatusHistoryFromInterfaceToModel(object map[string]interface{}) *models.StatusHistory {
+	history := models.StatusHistory{}
+
+	history.PreviousStatus = uint(toInt64(object["previous_status"]))
+	history.Status = uint(toInt64(object["status"]))
+	history.Version = uint(toInt64(object["version"]))
+
+	if statusAt, ok := object["status_at"].(primitive.DateTime); ok {
+		history.StatusAt = statusAt.Time().UTC()
+	}
+
+	if source, ok := object["source"].(string); ok {
+		history.Source = source
+	}
+
+	return &history
+}
+
 func (r *OrderRepository) mapOrderProducts(orderProducts []*models.Product) []map[string]interface{} {
 	var products []map[string]interface{}
 	for _, product := range orderProducts {
@@ -297,6 +875,7 @@ func (r *OrderRepository) mapOrderProducts(orderProducts []*models.Product) []ma
 			"_id":         product.ID.String(),
 			"Name":        product.Name,
 			"Description": product.Description,
+			"Category":    product.Category,
 			"Price":       product.Price,
 			"Quantity":    product.Quantity,
 			"Image":       product.Image,
@@ -321,3 +900,171 @@ func (r *OrderRepository) mapOrderStores(orderStores []*models.Store) []map[stri
 
 	return stores
 }
+
+func (r *OrderRepository) mapOrderLines(orderLines []*models.OrderLine) []map[string]interface{} {
+	var lines []map[string]interface{}
+	for _, line := range orderLines {
+		modelLine := map[string]interface{}{
+			"product_id": line.ProductID.String(),
+			"quantity":   line.Quantity,
+			"category":   line.Category,
+			"currency":   line.Currency,
+			"unit_price": line.UnitPrice,
+			"total":      line.Total,
+		}
+
+		lines = append(lines, modelLine)
+	}
+
+	return lines
+}
+
+func (r *OrderRepository) mapOrderTaxLines(orderTaxLines []*models.TaxLine) []map[string]interface{} {
+	var taxLines []map[string]interface{}
+	for _, taxLine := range orderTaxLines {
+		modelTaxLine := map[string]interface{}{
+			"product_id": taxLine.ProductID.String(),
+			"region":     taxLine.Region,
+			"category":   taxLine.Category,
+			"name":       taxLine.Name,
+			"rate":       taxLine.Rate,
+			"taxable":    taxLine.Taxable,
+			"amount":     taxLine.Amount,
+		}
+
+		taxLines = append(taxLines, modelTaxLine)
+	}
+
+	return taxLines
+}
+
+func (r *OrderRepository) mapOrderPromotions(orderPromotions []*models.AppliedPromotion) []map[string]interface{} {
+	var promotions []map[string]interface{}
+	for _, promotion := range orderPromotions {
+		modelPromotion := map[string]interface{}{
+			"code":   promotion.Code,
+			"name":   promotion.Name,
+			"type":   promotion.Type,
+			"amount": promotion.Amount,
+		}
+
+		promotions = append(promotions, modelPromotion)
+	}
+
+	return promotions
+}
+
+func (r *OrderRepository) mapOrderCanceledItems(orderCanceledItems []*models.CanceledItem) []map[string]interface{} {
+	var canceledItems []map[string]interface{}
+	for _, canceledItem := range orderCanceledItems {
+		modelCanceledItem := map[string]interface{}{
+			"product_id":  canceledItem.ProductID.String(),
+			"quantity":    canceledItem.Quantity,
+			"amount":      canceledItem.Amount,
+			"canceled_at": canceledItem.CanceledAt,
+			"source":      canceledItem.Source,
+		}
+
+		canceledItems = append(canceledItems, modelCanceledItem)
+	}
+
+	return canceledItems
+}
+
+func (r *OrderRepository) mapOrderReturns(orderReturns []*models.ReturnRequest) []map[string]interface{} {
+	var returns []map[string]interface{}
+	for _, returnRequest := range orderReturns {
+		var items []map[string]interface{}
+		for _, item := range returnRequest.Items {
+			items = append(items, map[string]interface{}{
+				"product_id": item.ProductID.String(),
+				"quantity":   item.Quantity,
+				"reason":     item.Reason,
+				"comment":    item.Comment,
+			})
+		}
+
+		modelReturn := map[string]interface{}{
+			"_id":          returnRequest.ID,
+			"items":        items,
+			"status":       returnRequest.Status,
+			"refund":       returnRequest.Refund,
+			"note":         returnRequest.Note,
+			"requested_by": returnRequest.RequestedBy,
+			"updated_by":   returnRequest.UpdatedBy,
+			"requested_at": returnRequest.RequestedAt,
+			"updated_at":   returnRequest.UpdatedAt,
+		}
+
+		returns = append(returns, modelReturn)
+	}
+
+	return returns
+}
+
+func (r *OrderRepository) mapOrderShipments(orderShipments []*models.Shipment) []map[string]interface{} {
+	var shipments []map[string]interface{}
+	for _, shipment := range orderShipments {
+		var productIDs []string
+		for _, productID := range shipment.ProductIDs {
+			productIDs = append(productIDs, productID.String())
+		}
+
+		modelShipment := map[string]interface{}{
+			"_id":             shipment.ID,
+			"store_id":        shipment.StoreID.String(),
+			"product_ids":     productIDs,
+			"status":          shipment.Status,
+			"carrier":         shipment.Carrier,
+			"tracking_number": shipment.TrackingNumber,
+			"created_at":      shipment.CreatedAt,
+			"updated_at":      shipment.UpdatedAt,
+		}
+
+		if !shipment.PackedAt.IsZero() {
+			modelShipment["packed_at"] = shipment.PackedAt
+		}
+
+		if !shipment.ShippedAt.IsZero() {
+			modelShipment["shipped_at"] = shipment.ShippedAt
+		}
+
+		if !shipment.DeliveredAt.IsZero() {
+			modelShipment["delivered_at"] = shipment.DeliveredAt
+		}
+
+		shipments = append(shipments, modelShipment)
+	}
+
+	return shipments
+}
+
+func (r *OrderRepository) mapOrderStatusHistory(orderHistory []*models.StatusHistory) []map[string]interface{} {
+	var history []map[string]interface{}
+	for _, entry := range orderHistory {
+		modelHistory := map[string]interface{}{
+			"previous_status": entry.PreviousStatus,
+			"status":          entry.Status,
+			"status_at":       entry.StatusAt,
+			"source":          entry.Source,
+			"version":         entry.Version,
+		}
+
+		history = append(history, modelHistory)
+	}
+
+	return history
+}
+
+func toInt64(value interface{}) int64 {
+	switch number := value.(type) {
+	case int32:
+		return int64(number)
+	case int64:
+		return number
+	case float64:
+		return int64(number)
+	}
+
+	return 0
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrSagaVersionConflict = errors.New("order saga was changed concurrently")
+
+type OrderSagaRepository struct {
+	database *mongo.Database
+}
+
+func NewOrderSagaRepository(
+	database *mongo.Database,
+) *OrderSagaRepository {
+	return &OrderSagaRepository{
+		database: database,
+	}
+}
+
+func (r *OrderSagaRepository) collection() *mongo.Collection {
+	return r.database.Collection("order_sagas")
+}
+
+func (r *OrderSagaRepository) FindByOrderID(ctx context.Context, orderID primitive.ObjectID) (*models.OrderSaga, error) {
+	saga := &models.OrderSaga{}
+	err := r.collection().FindOne(ctx, bson.M{"_id": orderID}).Decode(saga)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return saga, nil
+}
+
+func (r *OrderSagaRepository) FindStalled(ctx context.Context, before time.Time, limit int64) ([]*models.OrderSaga, error) {
+	filter := bson.M{
+		"status": models.SagaRunning,
+		"steps": bson.M{"$elemMatch": bson.M{
+			"status":          models.SagaStepStarted,
+			"last_attempt_at": bson.M{"$lt": before},
+		}},
+	}
+
+	findOptions := options.FindOptions{}
+	findOptions.SetSort(bson.D{{Key: "updated_at", Value: 1}})
+	findOptions.SetLimit(limit)
+
+	cursor, err := r.collection().Find(ctx, filter, &findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sagas := []*models.OrderSaga{}
+	err = cursor.All(ctx, &sagas)
+	if err != nil {
+		return nil, err
+	}
+
+	return sagas, nil
+}
+
+func (r *OrderSagaRepository) Create(ctx context.Context, saga *models.OrderSaga) error {
+	_, err := r.collection().InsertOne(ctx, saga)
+
+	return err
+}
+
+func (r *OrderSagaRepository) Update(ctx context.Context, saga *models.OrderSaga) error {
+	filter := bson.M{
+		"_id":     saga.OrderID,
+		"version": saga.Version,
+	}
+
+	saga.Version++
+	saga.UpdatedAt = time.Now().UTC()
+
+	result, err := r.collection().ReplaceOne(ctx, filter, saga)
+	if err != nil {
+		saga.Version--
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		saga.Version--
+		return ErrSagaVersionConflict
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OutboxRepository struct {
+	database *mongo.Database
+}
+
+func NewOutboxRepository(
+	database *mongo.Database,
+) *OutboxRepository {
+	return &OutboxRepository{
+		database: database,
+	}
+}
+
+func (r *OutboxRepository) collection() *mongo.Collection {
+	return r.database.Collection("outbox")
+}
+
+func (r *OutboxRepository) Add(ctx context.Context, message *models.OutboxMessage) error {
+	if message.ID.IsZero() {
+		message.ID = primitive.NewObjectID()
+	}
+
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = time.Now().UTC()
+	}
+
+	if message.NextAttemptAt.IsZero() {
+		message.NextAttemptAt = message.CreatedAt
+	}
+
+	_, err := r.collection().InsertOne(ctx, message)
+
+	return err
+}
+
+func (r *OutboxRepository) FindPending(ctx context.Context, now time.Time, limit int64) ([]*models.OutboxMessage, error) {
+	findOptions := options.FindOptions{}
+	findOptions.SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	findOptions.SetLimit(limit)
+
+	filter := bson.M{
+		"published":       false,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+
+	cursor, err := r.collection().Find(ctx, filter, &findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []*models.OutboxMessage{}
+	err = cursor.All(ctx, &messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// FindBackingOff returns the orders that have a message waiting on backoff.
+// Their later messages must wait too so an order's messages stay in order.
+func (r *OutboxRepository) FindBackingOff(ctx context.Context, now time.Time) ([]primitive.ObjectID, error) {
+	filter := bson.M{
+		"published":       false,
+		"next_attempt_at": bson.M{"$gt": now},
+	}
+
+	values, err := r.collection().Distinct(ctx, "order_id", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	orderIDs := []primitive.ObjectID{}
+	for _, value := range values {
+		if orderID, ok := value.(primitive.ObjectID); ok {
+			orderIDs = append(orderIDs, orderID)
+		}
+	}
+
+	return orderIDs, nil
+}
+
+// MarkPublished also drops the payload: some commands carry card data, and a
+// published message is only kept until the TTL index removes it.
+func (r *OutboxRepository) MarkPublished(ctx context.Context, ID primitive.ObjectID) error {
+	fields := bson.M{
+		"published":    true,
+		"published_at": time.Now().UTC(),
+	}
+
+	update := bson.M{
+		"$set":   fields,
+		"$unset": bson.M{"data": ""},
+		"$inc":   bson.M{"attempts": 1},
+	}
+
+	_, err := r.collection().UpdateByID(ctx, ID, update)
+
+	return err
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, ID primitive.ObjectID, lastError string, nextAttemptAt time.Time) error {
+	fields := bson.M{
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+	}
+
+	_, err := r.collection().UpdateByID(ctx, ID, bson.M{"$set": fields, "$inc": bson.M{"attempts": 1}})
+
+	return err
+}
+
+// Scrub replaces the payload of a message that is still waiting to be
+// published, so card data does not outlive a message that keeps failing.
+func (r *OutboxRepository) Scrub(ctx context.Context, ID primitive.ObjectID, data string) error {
+	filter := bson.M{
+		"_id":       ID,
+		"published": false,
+	}
+
+	_, err := r.collection().UpdateOne(ctx, filter, bson.M{"$set": bson.M{"data": data}})
+
+	return err
+}
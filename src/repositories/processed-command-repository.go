@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ProcessedCommandRepository struct {
+	database *mongo.Database
+}
+
+func NewProcessedCommandRepository(
+	database *mongo.Database,
+) *ProcessedCommandRepository {
+	return &ProcessedCommandRepository{
+		database: database,
+	}
+}
+
+func (r *ProcessedCommandRepository) collection() *mongo.Collection {
+	return r.database.Collection("processed_commands")
+}
+
+func (r *ProcessedCommandRepository) Begin(ctx context.Context, key string, subject string, lockedUntil time.Time, expiresAt time.Time) (bool, error) {
+	now := time.Now().UTC()
+
+	processedCommand := &models.ProcessedCommand{
+		Key:         key,
+		Subject:     subject,
+		Status:      models.CommandProcessing,
+		LockedUntil: lockedUntil,
+		CreatedAt:   now,
+		ExpiresAt:   expiresAt,
+	}
+
+	_, err := r.collection().InsertOne(ctx, processedCommand)
+	if err == nil {
+		return true, nil
+	}
+
+	if !mongo.IsDuplicateKeyError(err) {
+		return false, err
+	}
+
+	filter := bson.M{
+		"_id": key,
+		"$or": bson.A{
+			bson.M{"status": models.CommandFailed},
+			bson.M{"status": models.CommandProcessing, "locked_until": bson.M{"$lt": now}},
+		},
+	}
+
+	fields := bson.M{
+		"status":       models.CommandProcessing,
+		"locked_until": lockedUntil,
+		"expires_at":   expiresAt,
+	}
+
+	err = r.collection().FindOneAndUpdate(ctx, filter, bson.M{"$set": fields}).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *ProcessedCommandRepository) Complete(ctx context.Context, key string, status string, commandError string) error {
+	fields := bson.M{
+		"status":       status,
+		"error":        commandError,
+		"completed_at": time.Now().UTC(),
+	}
+
+	_, err := r.collection().UpdateByID(ctx, key, bson.M{"$set": fields})
+
+	return err
+}
+
+func (r *ProcessedCommandRepository) FindByKey(ctx context.Context, key string) (*models.ProcessedCommand, error) {
+	processedCommand := &models.ProcessedCommand{}
+	err := r.collection().FindOne(ctx, bson.M{"_id": key}).Decode(processedCommand)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return processedCommand, nil
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"order/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PromotionRedemptionRepository struct {
+	database *mongo.Database
+}
+
+func NewPromotionRedemptionRepository(
+	database *mongo.Database,
+) *PromotionRedemptionRepository {
+	return &PromotionRedemptionRepository{
+		database: database,
+	}
+}
+
+func (r *PromotionRedemptionRepository) redemptions() *mongo.Collection {
+	return r.database.Collection("promotion_redemptions")
+}
+
+func (r *PromotionRedemptionRepository) usages() *mongo.Collection {
+	return r.database.Collection("promotion_usages")
+}
+
+func usageKey(code string, customerID primitive.ObjectID) string {
+	return fmt.Sprintf("%s:%s", code, customerID.Hex())
+}
+
+func (r *PromotionRedemptionRepository) Usage(ctx context.Context, code string, customerID primitive.ObjectID) (uint, error) {
+	usage := struct {
+		Count int64 `bson:"count"`
+	}{}
+
+	err := r.usages().FindOne(ctx, bson.M{"_id": usageKey(code, customerID)}).Decode(&usage)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if usage.Count < 0 {
+		return 0, nil
+	}
+
+	return uint(usage.Count), nil
+}
+
+// Redeem counts one use of the promotion by the customer and records which
+// order used it. The counter is bumped with a conditional upsert, so two
+// concurrent orders cannot both take the last use: the loser hits the unique
+// _id and Redeem reports false.
+func (r *PromotionRedemptionRepository) Redeem(ctx context.Context, redemption *models.PromotionRedemption, limit uint) (bool, error) {
+	filter := bson.M{"_id": usageKey(redemption.Code, redemption.CustomerID)}
+	if limit > 0 {
+		filter["count"] = bson.M{"$lt": limit}
+	}
+
+	_, err := r.usages().UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"count": 1}}, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if redemption.ID.IsZero() {
+		redemption.ID = primitive.NewObjectID()
+	}
+
+	_, err = r.redemptions().InsertOne(ctx, redemption)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *PromotionRedemptionRepository) Release(ctx context.Context, orderID primitive.ObjectID) error {
+	cursor, err := r.redemptions().Find(ctx, bson.M{"order_id": orderID, "released": false})
+	if err != nil {
+		return err
+	}
+
+	var redemptions []*models.PromotionRedemption
+	err = cursor.All(ctx, &redemptions)
+	if err != nil {
+		return err
+	}
+
+	for _, redemption := range redemptions {
+		fields := bson.M{
+			"released":    true,
+			"released_at": time.Now().UTC(),
+		}
+
+		result, err := r.redemptions().UpdateOne(ctx, bson.M{"_id": redemption.ID, "released": false}, bson.M{"$set": fields})
+		if err != nil {
+			return err
+		}
+
+		if result.ModifiedCount == 0 {
+			continue
+		}
+
+		_, err = r.usages().UpdateOne(ctx, bson.M{"_id": usageKey(redemption.Code, redemption.CustomerID)}, bson.M{"$inc": bson.M{"count": -1}})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
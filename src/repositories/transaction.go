@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MongoTransaction struct {
+	client *mongo.Client
+}
+
+func NewMongoTransaction(
+	client *mongo.Client,
+) *MongoTransaction {
+	return &MongoTransaction{
+		client: client,
+	}
+}
+
+func (t *MongoTransaction) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionContext)
+	})
+
+	return err
+}
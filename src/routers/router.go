@@ -14,11 +14,22 @@ import (
 	common_service "github.com/JohnSalazar/microservices-go-common/services"
 )
 
+const (
+	adminClaim            = "order_admin"
+	adminSearchPermission = "search"
+	adminReadPermission   = "read"
+	adminStatusPermission = "update_status"
+	adminReturnPermission = "returns"
+	adminDlqPermission    = "dlq"
+)
+
 type Router struct {
-	config          *config.Config
-	serviceMetrics  common_service.Metrics
-	authentication  *middlewares.Authentication
-	orderController *controllers.OrderController
+	config               *config.Config
+	serviceMetrics       common_service.Metrics
+	authentication       *middlewares.Authentication
+	orderController      *controllers.OrderController
+	adminOrderController *controllers.AdminOrderController
+	deadLetterController *controllers.DeadLetterController
 }
 
 func NewRouter(
@@ -26,12 +37,16 @@ func NewRouter(
 	serviceMetrics common_service.Metrics,
 	authentication *middlewares.Authentication,
 	orderController *controllers.OrderController,
+	adminOrderController *controllers.AdminOrderController,
+	deadLetterController *controllers.DeadLetterController,
 ) *Router {
 	return &Router{
-		config:          config,
-		serviceMetrics:  serviceMetrics,
-		authentication:  authentication,
-		orderController: orderController,
+		config:               config,
+		serviceMetrics:       serviceMetrics,
+		authentication:       authentication,
+		orderController:      orderController,
+		adminOrderController: adminOrderController,
+		deadLetterController: deadLetterController,
 	}
 }
 
@@ -52,7 +67,70 @@ func (r *Router) RouterSetup() *gin.Engine {
 	v1.GET("/", r.authentication.Verify(),
 		r.orderController.GetAll)
 	v1.GET("/refresh", r.authentication.Verify(),
+		r.orderController.GetLast)
+
+	orders := v1.Group("/orders")
+	orders.POST("", r.authentication.Verify(),
+		r.orderController.Create)
+	orders.GET("/:id", r.authentication.Verify(),
 		r.orderController.GetById)
+	orders.PUT("/:id", r.authentication.Verify(),
+		r.orderController.Amend)
+	orders.GET("/:id/history", r.authentication.Verify(),
+		r.orderController.GetHistory)
+	orders.POST("/:id/cancel", r.authentication.Verify(),
+		r.orderController.Cancel)
+	orders.POST("/:id/items/cancel", r.authentication.Verify(),
+		r.orderController.CancelItems)
+	orders.GET("/:id/shipments", r.authentication.Verify(),
+		r.orderController.GetShipments)
+	orders.GET("/:id/returns", r.authentication.Verify(),
+		r.orderController.GetReturns)
+	orders.POST("/:id/returns", r.authentication.Verify(),
+		r.orderController.RequestReturn)
+
+	admin := v1.Group("/admin/orders")
+	admin.GET("", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminSearchPermission),
+		r.adminOrderController.Search)
+	admin.GET("/:id", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminReadPermission),
+		r.adminOrderController.GetById)
+	admin.GET("/:id/events", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminReadPermission),
+		r.adminOrderController.GetEvents)
+	admin.GET("/:id/saga", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminReadPermission),
+		r.adminOrderController.GetSaga)
+	admin.GET("/:id/replay", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminReadPermission),
+		r.adminOrderController.Replay)
+	admin.PUT("/:id/status", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminStatusPermission),
+		r.adminOrderController.UpdateStatus)
+	admin.GET("/:id/returns", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminReadPermission),
+		r.adminOrderController.GetReturns)
+	admin.PUT("/:id/returns/:returnId", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminReturnPermission),
+		r.adminOrderController.UpdateReturn)
+
+	dlq := v1.Group("/admin/dlq")
+	dlq.GET("", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminDlqPermission),
+		r.deadLetterController.GetAll)
+	dlq.GET("/:seq", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminDlqPermission),
+		r.deadLetterController.GetBySequence)
+	dlq.PUT("/:seq", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminDlqPermission),
+		r.deadLetterController.Resubmit)
+	dlq.DELETE("/:seq", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminDlqPermission),
+		r.deadLetterController.Discard)
+	dlq.POST("/replay", r.authentication.Verify(),
+		middlewares.Authorization(adminClaim, adminDlqPermission),
+		r.deadLetterController.Replay)
 
 	return router
 }
@@ -0,0 +1,127 @@
+package settings
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+type Settings struct {
+	Outbox      OutboxSettings      `json:"outbox"`
+	Idempotency IdempotencySettings `json:"idempotency"`
+	Money       MoneySettings       `json:"money"`
+	Pricing     PricingSettings     `json:"pricing"`
+	Tax         TaxSettings         `json:"tax"`
+	Promotions  PromotionsSettings  `json:"promotions"`
+	Saga        SagaSettings        `json:"saga"`
+	Expiry      ExpirySettings      `json:"expiry"`
+	Retry       RetrySettings       `json:"retry"`
+}
+
+type OutboxSettings struct {
+	IntervalSeconds   int `json:"intervalSeconds"`
+	BatchSize         int `json:"batchSize"`
+	MaxBackoffSeconds int `json:"maxBackoffSeconds"`
+	AlertAttempts     int `json:"alertAttempts"`
+}
+
+type IdempotencySettings struct {
+	WindowMinutes            int `json:"windowMinutes"`
+	ProcessingTimeoutSeconds int `json:"processingTimeoutSeconds"`
+}
+
+type MoneySettings struct {
+	DefaultCurrency   string `json:"defaultCurrency"`
+	BaseCurrency      string `json:"baseCurrency"`
+	ExchangeRatesFile string `json:"exchangeRatesFile"`
+}
+
+type PricingSettings struct {
+	ToleranceMinorUnits int64 `json:"toleranceMinorUnits"`
+}
+
+type TaxSettings struct {
+	DefaultRegion string    `json:"defaultRegion"`
+	Rules         []TaxRule `json:"rules"`
+}
+
+type TaxRule struct {
+	Region   string `json:"region"`
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Rate     string `json:"rate"`
+}
+
+type PromotionsSettings struct {
+	File string `json:"file"`
+}
+
+type SagaSettings struct {
+	IntervalSeconds    int `json:"intervalSeconds"`
+	StepTimeoutSeconds int `json:"stepTimeoutSeconds"`
+	MaxAttempts        int `json:"maxAttempts"`
+}
+
+type ExpirySettings struct {
+	IntervalSeconds int             `json:"intervalSeconds"`
+	BatchSize       int             `json:"batchSize"`
+	Timeouts        []ExpiryTimeout `json:"timeouts"`
+}
+
+// ExpiryTimeout cancels orders that stayed in Status for longer than Minutes.
+type ExpiryTimeout struct {
+	Status  uint `json:"status"`
+	Minutes int  `json:"minutes"`
+}
+
+type RetrySettings struct {
+	MaxDeliveries         int    `json:"maxDeliveries"`
+	InitialBackoffSeconds int    `json:"initialBackoffSeconds"`
+	MaxBackoffSeconds     int    `json:"maxBackoffSeconds"`
+	DeadLetterStream      string `json:"deadLetterStream"`
+}
+
+func LoadSettings(production bool, path string) *Settings {
+	v := viper.New()
+	v.AddConfigPath(path)
+	v.SetConfigName("config-dev")
+	if production {
+		v.SetConfigName("config-prod")
+	}
+	v.SetConfigType("json")
+
+	v.SetDefault("outbox.intervalSeconds", 2)
+	v.SetDefault("outbox.batchSize", 100)
+	v.SetDefault("outbox.maxBackoffSeconds", 300)
+	v.SetDefault("outbox.alertAttempts", 5)
+	v.SetDefault("idempotency.windowMinutes", 1440)
+	v.SetDefault("idempotency.processingTimeoutSeconds", 60)
+	v.SetDefault("money.defaultCurrency", "BRL")
+	v.SetDefault("money.baseCurrency", "BRL")
+	v.SetDefault("money.exchangeRatesFile", "./config/exchange-rates.json")
+	v.SetDefault("pricing.toleranceMinorUnits", 0)
+	v.SetDefault("tax.defaultRegion", "BR")
+	v.SetDefault("promotions.file", "./config/promotions.json")
+	v.SetDefault("saga.intervalSeconds", 30)
+	v.SetDefault("saga.stepTimeoutSeconds", 600)
+	v.SetDefault("saga.maxAttempts", 3)
+	v.SetDefault("expiry.intervalSeconds", 60)
+	v.SetDefault("expiry.batchSize", 100)
+	v.SetDefault("retry.maxDeliveries", 5)
+	v.SetDefault("retry.initialBackoffSeconds", 1)
+	v.SetDefault("retry.maxBackoffSeconds", 60)
+	v.SetDefault("retry.deadLetterStream", "order_dlq")
+
+	err := v.ReadInConfig()
+	if err != nil {
+		panic(fmt.Errorf("fatal error settings file: %s", err))
+	}
+
+	settings := &Settings{}
+	err = v.Unmarshal(settings)
+	if err != nil {
+		panic(fmt.Errorf("fatal error unmarshal settings: %s", err))
+	}
+
+	return settings
+}
@@ -0,0 +1,111 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"order/src/application/commands"
+	"order/src/repositories/interfaces"
+	"order/src/settings"
+
+	common_models "github.com/JohnSalazar/microservices-go-common/models"
+	trace "github.com/JohnSalazar/microservices-go-common/trace/otel"
+)
+
+const orderExpiryLock = "order-expiry"
+
+const orderExpirySource = "system:expiry"
+
+// OrderExpiryTask cancels orders that stayed in a status longer than its
+// configured timeout, e.g. when the payment service never answers.
+type OrderExpiryTask struct {
+	settings            *settings.ExpirySettings
+	orderRepository     interfaces.OrderRepository
+	lockRepository      interfaces.LockRepository
+	orderCommandHandler *commands.OrderCommandHandler
+	owner               string
+}
+
+func NewOrderExpiryTask(
+	settings *settings.ExpirySettings,
+	orderRepository interfaces.OrderRepository,
+	lockRepository interfaces.LockRepository,
+	orderCommandHandler *commands.OrderCommandHandler,
+	owner string,
+) *OrderExpiryTask {
+	return &OrderExpiryTask{
+		settings:            settings,
+		orderRepository:     orderRepository,
+		lockRepository:      lockRepository,
+		orderCommandHandler: orderCommandHandler,
+		owner:               owner,
+	}
+}
+
+func (task *OrderExpiryTask) Start(ctx context.Context) {
+	if len(task.settings.Timeouts) == 0 {
+		return
+	}
+
+	interval := time.Duration(task.settings.IntervalSeconds) * time.Second
+	ticker := time.NewTicker(interval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				err := task.expire(ctx, interval)
+				if err != nil {
+					log.Printf("order expiry error: %v\n", err)
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (task *OrderExpiryTask) expire(ctx context.Context, interval time.Duration) error {
+	ctx, span := trace.NewSpan(ctx, "OrderExpiryTask.expire")
+	defer span.End()
+
+	acquired, err := task.lockRepository.TryAcquire(ctx, orderExpiryLock, task.owner, 3*interval)
+	if err != nil || !acquired {
+		return err
+	}
+
+	for _, timeout := range task.settings.Timeouts {
+		if timeout.Status == uint(common_models.OrderCanceled) || timeout.Minutes <= 0 {
+			continue
+		}
+
+		before := time.Now().UTC().Add(-time.Duration(timeout.Minutes) * time.Minute)
+		orders, err := task.orderRepository.FindExpired(ctx, timeout.Status, before, int64(task.settings.BatchSize))
+		if err != nil {
+			return err
+		}
+
+		for _, order := range orders {
+			status := timeout.Status
+			command := &commands.UpdateStatusOrderCommand{
+				ID:       order.ID,
+				Status:   uint(common_models.OrderCanceled),
+				StatusAt: time.Now().UTC(),
+				Source:   fmt.Sprintf("%s:%d", orderExpirySource, timeout.Minutes),
+				Expected: &status,
+			}
+
+			// Another replica or a late payment reply may have changed the
+			// order meanwhile; Expected and the version check make that a no-op
+			// or a conflict that the next run skips.
+			err = task.orderCommandHandler.UpdateStatusOrderCommandHandler(ctx, command)
+			if err != nil {
+				log.Printf("order expiry %s error: %v\n", order.ID.Hex(), err)
+			}
+		}
+	}
+
+	return nil
+}
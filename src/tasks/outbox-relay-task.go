@@ -0,0 +1,175 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"order/src/application/sensitive"
+	"order/src/models"
+	"order/src/repositories/interfaces"
+	"order/src/settings"
+
+	common_service "github.com/JohnSalazar/microservices-go-common/services"
+	trace "github.com/JohnSalazar/microservices-go-common/trace/otel"
+	"github.com/nats-io/nats.go"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const outboxRelayLock = "outbox-relay"
+
+// MsgPublisher publishes with headers, which the relay needs to set
+// Nats-Msg-Id; nats.JetStreamContext implements it.
+type MsgPublisher interface {
+	PublishMsg(msg *nats.Msg, opts ...nats.PubOpt) (*nats.PubAck, error)
+}
+
+type OutboxRelayTask struct {
+	settings         *settings.OutboxSettings
+	outboxRepository interfaces.OutboxRepository
+	lockRepository   interfaces.LockRepository
+	publisher        MsgPublisher
+	email            common_service.EmailService
+	owner            string
+}
+
+func NewOutboxRelayTask(
+	settings *settings.OutboxSettings,
+	outboxRepository interfaces.OutboxRepository,
+	lockRepository interfaces.LockRepository,
+	publisher MsgPublisher,
+	email common_service.EmailService,
+	owner string,
+) *OutboxRelayTask {
+	return &OutboxRelayTask{
+		settings:         settings,
+		outboxRepository: outboxRepository,
+		lockRepository:   lockRepository,
+		publisher:        publisher,
+		email:            email,
+		owner:            owner,
+	}
+}
+
+func (task *OutboxRelayTask) Start(ctx context.Context) {
+	interval := time.Duration(task.settings.IntervalSeconds) * time.Second
+	ticker := time.NewTicker(interval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				err := task.relay(ctx, interval)
+				if err != nil {
+					log.Printf("outbox relay error: %v\n", err)
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (task *OutboxRelayTask) relay(ctx context.Context, interval time.Duration) error {
+	_, span := trace.NewSpan(ctx, "OutboxRelayTask.relay")
+	defer span.End()
+
+	acquired, err := task.lockRepository.TryAcquire(ctx, outboxRelayLock, task.owner, 3*interval)
+	if err != nil || !acquired {
+		return err
+	}
+
+	now := time.Now().UTC()
+	backingOff, err := task.outboxRepository.FindBackingOff(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	blocked := map[primitive.ObjectID]bool{}
+	for _, orderID := range backingOff {
+		blocked[orderID] = true
+	}
+
+	messages, err := task.outboxRepository.FindPending(ctx, now, int64(task.settings.BatchSize))
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		if blocked[message.OrderID] {
+			continue
+		}
+
+		err = task.publish(message)
+		if err != nil {
+			blocked[message.OrderID] = true
+			task.failed(ctx, message, err)
+			continue
+		}
+
+		err = task.outboxRepository.MarkPublished(ctx, message.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (task *OutboxRelayTask) publish(message *models.OutboxMessage) error {
+	msg := nats.NewMsg(message.Subject)
+	msg.Data = []byte(message.Data)
+	if len(message.MsgID) > 0 {
+		msg.Header.Set(nats.MsgIdHdr, message.MsgID)
+	}
+
+	_, err := task.publisher.PublishMsg(msg)
+
+	return err
+}
+
+func (task *OutboxRelayTask) failed(ctx context.Context, message *models.OutboxMessage, err error) {
+	attempts := message.Attempts + 1
+
+	backoff := time.Duration(math.Pow(2, float64(attempts))) * time.Second
+	maxBackoff := time.Duration(task.settings.MaxBackoffSeconds) * time.Second
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	markErr := task.outboxRepository.MarkFailed(ctx, message.ID, err.Error(), time.Now().UTC().Add(backoff))
+	if markErr != nil {
+		log.Printf("outbox mark failed error: %v\n", markErr)
+	}
+
+	if attempts < uint(task.settings.AlertAttempts) {
+		return
+	}
+
+	scrubbed := task.scrub(ctx, message)
+
+	if attempts == uint(task.settings.AlertAttempts) {
+		go task.email.SendSupportMessage(fmt.Sprintf("Order ID: %s outbox message %s to %s failed %d times (card data removed: %t): %s",
+			message.OrderID.Hex(), message.ID.Hex(), message.Subject, attempts, scrubbed, err.Error()))
+	}
+}
+
+// scrub removes card data from a message that reached the alert threshold.
+// If it is published later, the payment service rejects it for missing card
+// data and the customer has to pay again.
+func (task *OutboxRelayTask) scrub(ctx context.Context, message *models.OutboxMessage) bool {
+	data, redacted := sensitive.Redact([]byte(message.Data))
+	if !redacted {
+		return false
+	}
+
+	err := task.outboxRepository.Scrub(ctx, message.ID, string(data))
+	if err != nil {
+		log.Printf("outbox scrub error: %v\n", err)
+		return false
+	}
+
+	return true
+}
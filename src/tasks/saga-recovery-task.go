@@ -0,0 +1,107 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"order/src/application/saga"
+	"order/src/repositories/interfaces"
+	"order/src/settings"
+
+	common_service "github.com/JohnSalazar/microservices-go-common/services"
+	trace "github.com/JohnSalazar/microservices-go-common/trace/otel"
+)
+
+const sagaRecoveryLock = "saga-recovery"
+
+const sagaRecoveryBatchSize = 100
+
+// SagaRecoveryTask resends saga steps that got no reply within the step
+// timeout, which also resumes sagas interrupted by a restart.
+type SagaRecoveryTask struct {
+	settings       *settings.SagaSettings
+	sagaRepository interfaces.OrderSagaRepository
+	lockRepository interfaces.LockRepository
+	transaction    interfaces.Transaction
+	orderSaga      *saga.OrderSagaOrchestrator
+	email          common_service.EmailService
+	owner          string
+}
+
+func NewSagaRecoveryTask(
+	settings *settings.SagaSettings,
+	sagaRepository interfaces.OrderSagaRepository,
+	lockRepository interfaces.LockRepository,
+	transaction interfaces.Transaction,
+	orderSaga *saga.OrderSagaOrchestrator,
+	email common_service.EmailService,
+	owner string,
+) *SagaRecoveryTask {
+	return &SagaRecoveryTask{
+		settings:       settings,
+		sagaRepository: sagaRepository,
+		lockRepository: lockRepository,
+		transaction:    transaction,
+		orderSaga:      orderSaga,
+		email:          email,
+		owner:          owner,
+	}
+}
+
+func (task *SagaRecoveryTask) Start(ctx context.Context) {
+	interval := time.Duration(task.settings.IntervalSeconds) * time.Second
+	ticker := time.NewTicker(interval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				err := task.recover(ctx, interval)
+				if err != nil {
+					log.Printf("saga recovery error: %v\n", err)
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (task *SagaRecoveryTask) recover(ctx context.Context, interval time.Duration) error {
+	_, span := trace.NewSpan(ctx, "SagaRecoveryTask.recover")
+	defer span.End()
+
+	acquired, err := task.lockRepository.TryAcquire(ctx, sagaRecoveryLock, task.owner, 3*interval)
+	if err != nil || !acquired {
+		return err
+	}
+
+	before := time.Now().UTC().Add(-time.Duration(task.settings.StepTimeoutSeconds) * time.Second)
+	sagas, err := task.sagaRepository.FindStalled(ctx, before, sagaRecoveryBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, orderSaga := range sagas {
+		err = task.transaction.WithTransaction(ctx, func(ctx context.Context) error {
+			stalled, err := task.orderSaga.Retry(ctx, orderSaga, before, uint(task.settings.MaxAttempts))
+			if err != nil {
+				return err
+			}
+
+			if stalled != nil {
+				go task.email.SendSupportMessage(fmt.Sprintf("Order ID: %s saga stalled: %s",
+					orderSaga.OrderID.Hex(), orderSaga.Reason))
+			}
+
+			return nil
+		})
+		if err != nil {
+			log.Printf("saga recovery order %s error: %v\n", orderSaga.OrderID.Hex(), err)
+		}
+	}
+
+	return nil
+}
@@ -12,11 +12,83 @@ import (
 )
 
 type addOrder struct {
-	ID         primitive.ObjectID `from:"id" json:"id" validate:"required"`
-	CustomerID primitive.ObjectID `from:"customerId" json:"customerId" validate:"required"`
-	Products   []*models.Product  `from:"products" json:"products" validate:"required"`
-	Sum        float32            `from:"sum" json:"sum" validate:"required"`
-	Status     uint               `from:"status" json:"status"`
+	ID               primitive.ObjectID `from:"id" json:"id" validate:"required"`
+	CustomerID       primitive.ObjectID `from:"customerId" json:"customerId" validate:"required"`
+	Products         []*product         `from:"products" json:"products" validate:"required,min=1,dive,required"`
+	Sum              int64              `from:"sum" json:"sum" validate:"required,gt=0"`
+	Currency         string             `from:"currency" json:"currency" validate:"required,len=3"`
+	SumCurrency      string             `from:"sumCurrency" json:"sumCurrency" validate:"eqfield=Currency"`
+	Discount         int64              `from:"discount" json:"discount" validate:"gte=0,ltefield=Sum"`
+	DiscountCurrency string             `from:"discountCurrency" json:"discountCurrency" validate:"omitempty,eqfield=Currency"`
+	Region           string             `from:"region" json:"region" validate:"required,max=16"`
+	Coupons          []string           `from:"coupons" json:"coupons" validate:"max=5,dive,required,max=32"`
+	ShippingAddress  *address           `from:"shippingAddress" json:"shippingAddress" validate:"required_unless=DeliveryMethod pickup"`
+	BillingAddress   *address           `from:"billingAddress" json:"billingAddress"`
+	DeliveryMethod   string             `from:"deliveryMethod" json:"deliveryMethod" validate:"required,oneof=standard express pickup"`
+	ShippingCost     int64              `from:"shippingCost" json:"shippingCost" validate:"gte=0"`
+	ShippingCurrency string             `from:"shippingCurrency" json:"shippingCurrency" validate:"omitempty,eqfield=Currency"`
+	Status           uint               `from:"status" json:"status"`
+}
+
+type address struct {
+	Name       string `from:"name" json:"name" validate:"required,max=100"`
+	Street     string `from:"street" json:"street" validate:"required,max=150"`
+	Number     string `from:"number" json:"number" validate:"required,max=20"`
+	Complement string `from:"complement" json:"complement" validate:"max=100"`
+	District   string `from:"district" json:"district" validate:"max=100"`
+	City       string `from:"city" json:"city" validate:"required,max=100"`
+	State      string `from:"state" json:"state" validate:"required,max=50"`
+	PostalCode string `from:"postalCode" json:"postalCode" validate:"required,max=20"`
+	Country    string `from:"country" json:"country" validate:"required,len=2"`
+	Phone      string `from:"phone" json:"phone" validate:"max=30"`
+}
+
+type product struct {
+	ID            uuid.UUID `from:"id" json:"id" validate:"required"`
+	Quantity      uint      `from:"quantity" json:"quantity" validate:"required,gt=0"`
+	Price         int64     `from:"price" json:"price" validate:"required,gt=0"`
+	PriceCurrency string    `from:"priceCurrency" json:"priceCurrency" validate:"eqfield=Currency"`
+	Currency      string    `from:"currency" json:"currency"`
+}
+
+type cancelItemsOrder struct {
+	ID    primitive.ObjectID `from:"id" json:"id" validate:"required"`
+	Items []*cancelItem      `from:"items" json:"items" validate:"required,min=1,dive,required"`
+}
+
+type cancelItem struct {
+	ProductID uuid.UUID `from:"productId" json:"productId" validate:"required"`
+	Quantity  uint      `from:"quantity" json:"quantity" validate:"required,gt=0"`
+}
+
+type requestReturn struct {
+	ID    primitive.ObjectID `from:"id" json:"id" validate:"required"`
+	Items []*returnItem      `from:"items" json:"items" validate:"required,min=1,dive,required"`
+}
+
+type returnItem struct {
+	ProductID uuid.UUID `from:"productId" json:"productId" validate:"required"`
+	Quantity  uint      `from:"quantity" json:"quantity" validate:"required,gt=0"`
+	Reason    string    `from:"reason" json:"reason" validate:"required,oneof=damaged wrong_item not_as_described no_longer_needed other"`
+	Comment   string    `from:"comment" json:"comment" validate:"max=500"`
+}
+
+type updateReturn struct {
+	ID             primitive.ObjectID `from:"id" json:"id" validate:"required"`
+	ReturnID       primitive.ObjectID `from:"returnId" json:"returnId" validate:"required"`
+	Status         string             `from:"status" json:"status" validate:"required,oneof=approved rejected received refunded"`
+	Note           string             `from:"note" json:"note" validate:"max=500"`
+	Refund         int64              `from:"refund" json:"refund" validate:"gte=0"`
+	RefundCurrency string             `from:"refundCurrency" json:"refundCurrency" validate:"omitempty,len=3"`
+}
+
+type updateShipmentOrder struct {
+	ID             primitive.ObjectID `from:"orderId" json:"orderId" validate:"required"`
+	ShipmentID     primitive.ObjectID `from:"shipmentId" json:"shipmentId"`
+	StoreID        uuid.UUID          `from:"storeId" json:"storeId" validate:"required"`
+	Status         string             `from:"status" json:"status" validate:"required,oneof=packed shipped delivered"`
+	Carrier        string             `from:"carrier" json:"carrier" validate:"max=100"`
+	TrackingNumber string             `from:"trackingNumber" json:"trackingNumber" validate:"max=100"`
 }
 
 type updateStatusOrder struct {
@@ -32,11 +104,28 @@ type updateStoreOrder struct {
 
 func ValidateAddOrder(fields *dtos.AddOrder) interface{} {
 	addOrder := addOrder{
-		ID:         fields.ID,
-		CustomerID: fields.CustomerID,
-		Products:   fields.Products,
-		Sum:        fields.Sum,
-		Status:     fields.Status,
+		ID:              fields.ID,
+		CustomerID:      fields.CustomerID,
+		Products:        mapProducts(fields.Products, fields.Currency),
+		Sum:             fields.Sum.Amount,
+		Currency:        fields.Currency,
+		SumCurrency:     fields.Sum.Currency,
+		Discount:        fields.Discount.Amount,
+		Region:          fields.Region,
+		Coupons:         fields.Coupons,
+		ShippingAddress: mapAddress(fields.ShippingAddress),
+		BillingAddress:  mapAddress(fields.BillingAddress),
+		DeliveryMethod:  fields.DeliveryMethod,
+		ShippingCost:    fields.ShippingCost.Amount,
+		Status:          fields.Status,
+	}
+
+	if !fields.Discount.IsZero() {
+		addOrder.DiscountCurrency = fields.Discount.Currency
+	}
+
+	if !fields.ShippingCost.IsZero() {
+		addOrder.ShippingCurrency = fields.ShippingCost.Currency
 	}
 
 	err := common_validator.Validate(addOrder)
@@ -47,6 +136,97 @@ func ValidateAddOrder(fields *dtos.AddOrder) interface{} {
 	return nil
 }
 
+func ValidateCancelItemsOrder(fields *dtos.CancelItemsOrder) interface{} {
+	cancelItemsOrder := cancelItemsOrder{
+		ID: fields.ID,
+	}
+
+	for _, item := range fields.Items {
+		if item == nil {
+			cancelItemsOrder.Items = append(cancelItemsOrder.Items, nil)
+			continue
+		}
+
+		cancelItemsOrder.Items = append(cancelItemsOrder.Items, &cancelItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	err := common_validator.Validate(cancelItemsOrder)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ValidateRequestReturn(fields *dtos.RequestReturn) interface{} {
+	requestReturn := requestReturn{
+		ID: fields.ID,
+	}
+
+	for _, item := range fields.Items {
+		if item == nil {
+			requestReturn.Items = append(requestReturn.Items, nil)
+			continue
+		}
+
+		requestReturn.Items = append(requestReturn.Items, &returnItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Reason:    item.Reason,
+			Comment:   item.Comment,
+		})
+	}
+
+	err := common_validator.Validate(requestReturn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ValidateUpdateReturn(fields *dtos.UpdateReturn) interface{} {
+	updateReturn := updateReturn{
+		ID:       fields.ID,
+		ReturnID: fields.ReturnID,
+		Status:   fields.Status,
+		Note:     fields.Note,
+	}
+
+	if fields.Refund != nil {
+		updateReturn.Refund = fields.Refund.Amount
+		updateReturn.RefundCurrency = fields.Refund.Currency
+	}
+
+	err := common_validator.Validate(updateReturn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ValidateUpdateShipmentOrder(fields *dtos.UpdateShipmentOrder) interface{} {
+	updateShipmentOrder := updateShipmentOrder{
+		ID:             fields.ID,
+		ShipmentID:     fields.ShipmentID,
+		StoreID:        fields.StoreID,
+		Status:         fields.Status,
+		Carrier:        fields.Carrier,
+		TrackingNumber: fields.TrackingNumber,
+	}
+
+	err := common_validator.Validate(updateShipmentOrder)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func ValidateUpdateStatusOrder(fields *dtos.UpdateStatusOrder) interface{} {
 	updateStatusOrder := updateStatusOrder{
 		ID:       fields.ID,
@@ -75,3 +255,44 @@ func ValidateUpdateStoreOrder(fields *dtos.UpdateStoreOrder) interface{} {
 
 	return nil
 }
+
+func mapAddress(fields *models.Address) *address {
+	if fields == nil {
+		return nil
+	}
+
+	return &address{
+		Name:       fields.Name,
+		Street:     fields.Street,
+		Number:     fields.Number,
+		Complement: fields.Complement,
+		District:   fields.District,
+		City:       fields.City,
+		State:      fields.State,
+		PostalCode: fields.PostalCode,
+		Country:    fields.Country,
+		Phone:      fields.Phone,
+	}
+}
+
+// mapProducts carries the order currency into each product so its price can
+// be checked against it.
+func mapProducts(products []*models.Product, currency string) []*product {
+	result := []*product{}
+	for _, item := range products {
+		if item == nil {
+			result = append(result, nil)
+			continue
+		}
+
+		result = append(result, &product{
+			ID:            item.ID,
+			Quantity:      item.Quantity,
+			Price:         item.Price.Amount,
+			PriceCurrency: item.Price.Currency,
+			Currency:      currency,
+		})
+	}
+
+	return result
+}